@@ -6,18 +6,35 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/shennawardana23/graphql-pba/graph"
 	"github.com/shennawardana23/graphql-pba/graph/generated"
+	"github.com/shennawardana23/graphql-pba/internal/app/accesslog"
 	"github.com/shennawardana23/graphql-pba/internal/app/database"
+	"github.com/shennawardana23/graphql-pba/internal/app/health"
+	"github.com/shennawardana23/graphql-pba/internal/app/monitoring"
+	"github.com/shennawardana23/graphql-pba/internal/app/persistedquery"
+	"github.com/shennawardana23/graphql-pba/internal/app/querylimit"
+	"github.com/shennawardana23/graphql-pba/internal/app/ratelimit"
+	"github.com/shennawardana23/graphql-pba/internal/auth"
+	"github.com/shennawardana23/graphql-pba/internal/event"
 	"github.com/shennawardana23/graphql-pba/internal/middleware"
+	"github.com/shennawardana23/graphql-pba/internal/pubsub"
+	"github.com/shennawardana23/graphql-pba/internal/repository"
 	"github.com/shennawardana23/graphql-pba/internal/util/logger"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gin-gonic/gin"
+	"github.com/go-pg/pg/v10"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
@@ -27,15 +44,19 @@ import (
 
 const (
 	shutdownTimeout = 5 * time.Second
+
+	// defaultDrainDelay is how long readiness reports not-ready before the
+	// server stops accepting connections
+	defaultDrainDelay = 5 * time.Second
 )
 
 var (
 	requestCount = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "graphql_requests_total",
-			Help: "Total number of GraphQL requests",
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests",
 		},
-		[]string{"operation"},
+		[]string{"method", "path", "status"},
 	)
 )
 
@@ -44,24 +65,26 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
 	startTime := time.Now()
 
 	// Setup signal handling
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
-	// Create a logs directory if it doesn't exist
-	os.MkdirAll("logs", os.ModePerm)
-
-	// Create a log file
-	logFile, err := os.OpenFile("logs/app.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	// Send the logs to stdout, the rotated file and/or Fluent Bit
+	logOutputs, err := logger.Setup(logger.NewConfig())
 	if err != nil {
-		log.Fatalf("error opening log file: %v", err)
+		log.Fatalf("error configuring logger: %v", err)
 	}
-	defer logFile.Close()
+	defer logOutputs.Close()
 
-	// Set log output to the log file
-	log.SetOutput(logFile)
+	// Route the standard library logger through the same outputs
+	log.SetFlags(0)
+	log.SetOutput(logger.Log.Writer())
 
 	// Initialize database
 	db := database.Connect()
@@ -72,16 +95,78 @@ func main() {
 		}
 	}()
 
-	// Create resolver with dependencies
-	resolver := graph.NewResolver(db)
+	// Initialize token service
+	tokens, err := auth.NewTokenService(auth.NewConfig())
+	if err != nil {
+		logger.Log.Fatalf("Invalid auth configuration: %v", err)
+	}
 
-	// Create GraphQL server with custom error presenter
-	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{
-		Resolvers: resolver,
+	identity := auth.NewIdentityResolver(tokens)
+
+	// Change events reach the subscriptions of every instance through
+	// LISTEN/NOTIFY, unless EVENT_BROKER=memory for a single instance
+	var broker pubsub.Broker = pubsub.NewMemoryBroker()
+	if os.Getenv("EVENT_BROKER") != "memory" {
+		pgBroker := pubsub.NewPostgresBroker(db)
+		brokerCtx, stopBroker := context.WithCancel(context.Background())
+		defer stopBroker()
+		go pgBroker.Run(brokerCtx)
+		broker = pgBroker
+	}
+	bufferSize, _ := strconv.Atoi(os.Getenv("SUBSCRIPTION_BUFFER_SIZE"))
+	events := event.NewBus(broker, bufferSize,
+		repository.NewRestaurantRepository(db), repository.NewUserRepository(db))
+
+	// Create resolver with dependencies
+	resolver := graph.NewResolver(db, tokens, events)
+
+	// Create GraphQL server, same transports as handler.NewDefaultServer but
+	// with subscriptions authenticated from the connection_init payload
+	listCost, _ := strconv.Atoi(os.Getenv("QUERY_LIST_COST_MULTIPLIER"))
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{
+		Resolvers:  resolver,
+		Directives: graph.NewDirectiveRoot(resolver),
+		Complexity: graph.NewComplexityRoot(listCost),
 	}))
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		InitFunc:              middleware.WebsocketInit(identity),
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+	srv.SetQueryCache(lru.New(1000))
+	srv.Use(extension.Introspection{})
+	persisted := persistedQueries(db)
+	srv.Use(persisted)
+
+	// Reject deep, alias heavy or expensive operations before resolving them
+	srv.Use(querylimit.New(querylimit.NewConfig()))
+
+	// Charge every operation to its client by the weight of its document
+	rateLimitConfig := ratelimit.NewConfig()
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if os.Getenv("RATE_LIMIT_STORE") == "postgres" {
+		rateLimitStore = ratelimit.NewPostgresStore(db)
+	}
+	srv.Use(ratelimit.New(rateLimitConfig, rateLimitStore))
 
-	// Set custom error presenter
+	// Set custom error presenter, panics are reported as masked internal errors
 	srv.SetErrorPresenter(graph.ErrorPresenter)
+	srv.SetRecoverFunc(graph.Recover)
+
+	// Record per-operation metrics, resolver latency only when asked for.
+	// Operations are labelled by name when listed in GRAPHQL_METRICS_OPERATIONS
+	// or in the allowlist.
+	metricOperations := envList("GRAPHQL_METRICS_OPERATIONS")
+	if allowlist, ok := persisted.(*persistedquery.Allowlist); ok {
+		metricOperations = append(metricOperations, allowlist.OperationNames()...)
+	}
+	srv.Use(monitoring.NewGraphQLMetrics(prometheus.DefaultRegisterer, os.Getenv("GRAPHQL_FIELD_METRICS") == "true", metricOperations))
+
+	// Log every operation with its variables redacted and its database time
+	srv.Use(accesslog.New(accesslog.NewConfig()))
 
 	log.Println("GraphQL server created successfully")
 
@@ -89,16 +174,32 @@ func main() {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
 
+	// Only the proxies in TRUSTED_PROXIES may set the client IP through
+	// X-Forwarded-For, by default none is trusted
+	if err := r.SetTrustedProxies(envList("TRUSTED_PROXIES")); err != nil {
+		logger.Log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// Add custom logging middleware
 	r.Use(gin.Recovery())
+	r.Use(middleware.RequestID())
+	r.Use(middleware.Locale())
 	r.Use(loggerMiddleware())
 	r.Use(middleware.ErrorHandler())
 
 	// Metrics endpoint
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
-	// GraphQL endpoints
-	r.POST("/query", gin.WrapH(srv))
+	// Health probes
+	checker := health.NewChecker(db)
+	r.GET("/healthz", checker.Liveness)
+	r.GET("/readyz", checker.Readiness)
+
+	// GraphQL endpoints, GET also serves the WebSocket upgrade for subscriptions
+	graphqlHandler := gin.WrapH(graph.DataloaderMiddleware(resolver, srv))
+	rateLimit := middleware.RateLimit(rateLimitConfig)
+	r.POST("/query", middleware.Auth(identity), rateLimit, graphqlHandler)
+	r.GET("/query", middleware.Auth(identity), rateLimit, graphqlHandler)
 	r.GET("/", gin.WrapH(playground.Handler("GraphQL playground", "/query")))
 
 	port := os.Getenv("PORT")
@@ -125,6 +226,14 @@ func main() {
 	<-quit
 	logger.Log.Info("Shutting down server...")
 
+	// Fail readiness first so load balancers stop routing new requests
+	checker.SetDraining()
+	drainDelay := defaultDrainDelay
+	if value, err := time.ParseDuration(os.Getenv("SHUTDOWN_DRAIN_DELAY")); err == nil {
+		drainDelay = value
+	}
+	time.Sleep(drainDelay)
+
 	// Create shutdown context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
@@ -139,6 +248,37 @@ func main() {
 	logger.Log.Info("Server shutdown complete")
 }
 
+// persistedQueries returns the allowlist when PERSISTED_QUERIES_ALLOWLIST
+// names a manifest, so only its operations run, and automatic persisted
+// queries otherwise
+func persistedQueries(db *pg.DB) graphql.HandlerExtension {
+	if manifest := os.Getenv("PERSISTED_QUERIES_ALLOWLIST"); manifest != "" {
+		allowlist, err := persistedquery.LoadAllowlist(manifest)
+		if err != nil {
+			logger.Log.Fatalf("Invalid persisted query allowlist: %v", err)
+		}
+		logger.Log.Infof("Allowlist mode, %d operations allowed", allowlist.Len())
+		return allowlist
+	}
+
+	var store *repository.PersistedQueryRepository
+	if os.Getenv("PERSISTED_QUERIES_STORE") == "postgres" {
+		store = repository.NewPersistedQueryRepository(db)
+	}
+	return extension.AutomaticPersistedQuery{Cache: persistedquery.NewCache(persistedquery.NewConfig(), store)}
+}
+
+// envList reads a comma separated list from the environment
+func envList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 // Custom logging middleware for Gin
 func loggerMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -148,13 +288,13 @@ func loggerMiddleware() gin.HandlerFunc {
 		// Add request tracking
 		c.Next()
 
-		// Skip metrics endpoint logging
-		if path != "/metrics" {
+		// Skip metrics and probe endpoint logging
+		if path != "/metrics" && path != "/healthz" && path != "/readyz" {
 			// Increment the request count
-			requestCount.WithLabelValues(c.Request.Method).Inc()
+			requestCount.WithLabelValues(c.Request.Method, c.FullPath(), strconv.Itoa(c.Writer.Status())).Inc()
 
 			// Log request details
-			logger.Log.WithFields(logrus.Fields{
+			logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
 				"method":     c.Request.Method,
 				"path":       path,
 				"status":     c.Writer.Status(),
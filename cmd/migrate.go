@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/shennawardana23/graphql-pba/internal/app/database"
+	"github.com/shennawardana23/graphql-pba/internal/util/logger"
+	"github.com/shennawardana23/graphql-pba/migrations"
+)
+
+const migrateUsage = `Usage: go run ./cmd migrate <command> [arguments]
+
+Commands:
+  up              apply all pending migrations
+  down [steps]    revert the last applied migration(s), default 1
+  status          list migrations and whether they are applied
+  create <name>   write a new empty up/down pair to the migrations directory
+`
+
+// runMigrate handles the `migrate` subcommand and returns the process exit code
+func runMigrate(args []string) int {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dir := flags.String("dir", "migrations", "directory new migrations are created in")
+	flags.Usage = func() { fmt.Fprint(os.Stderr, migrateUsage) }
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	command, rest := flags.Arg(0), flags.Args()[1:]
+
+	if command == "create" {
+		if len(rest) != 1 {
+			flags.Usage()
+			return 2
+		}
+		files, err := database.CreateMigration(*dir, rest[0])
+		if err != nil {
+			logger.Log.Errorf("Failed to create migration: %v", err)
+			return 1
+		}
+		for _, file := range files {
+			fmt.Println("created", file)
+		}
+		return 0
+	}
+
+	db := database.Open()
+	defer db.Close()
+
+	migrator := database.NewMigrator(db, migrations.FS)
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			logger.Log.Infof("Applied migration %06d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			logger.Log.Errorf("Migration failed: %v", err)
+			return 1
+		}
+		if len(applied) == 0 {
+			logger.Log.Info("No pending migrations")
+		}
+
+	case "down":
+		steps := 1
+		if len(rest) > 0 {
+			n, err := strconv.Atoi(rest[0])
+			if err != nil || n < 1 {
+				flags.Usage()
+				return 2
+			}
+			steps = n
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			logger.Log.Infof("Reverted migration %06d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			logger.Log.Errorf("Migration failed: %v", err)
+			return 1
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			logger.Log.Errorf("Failed to read migration status: %v", err)
+			return 1
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%06d  %-40s  %s\n", s.Version, s.Name, applied)
+		}
+
+	default:
+		flags.Usage()
+		return 2
+	}
+
+	return 0
+}
@@ -0,0 +1,38 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/shennawardana23/graphql-pba/graph/model"
+	"github.com/shennawardana23/graphql-pba/internal/entity"
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+)
+
+// issueTokens creates a new access/refresh token pair for user
+func (r *Resolver) issueTokens(ctx context.Context, user *entity.User) (*model.AuthPayload, error) {
+	accessToken, expiresAt, err := r.Tokens.IssueAccessToken(user.ID, user.Email, []string{user.Role}, user.Locale)
+	if err != nil {
+		return nil, exception.ErrInternalServer.Wrap(err)
+	}
+
+	refreshToken, refreshHash, refreshExpiresAt, err := r.Tokens.NewRefreshToken()
+	if err != nil {
+		return nil, exception.ErrInternalServer.Wrap(err)
+	}
+
+	err = r.RefreshTokenRepository.Create(ctx, &entity.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: refreshHash,
+		ExpiresAt: refreshExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AuthPayload{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: expiresAt,
+		RefreshToken:         refreshToken,
+		User:                 newUserModel(user),
+	}, nil
+}
@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"github.com/shennawardana23/graphql-pba/graph/generated"
+	"github.com/shennawardana23/graphql-pba/graph/model"
+	"github.com/shennawardana23/graphql-pba/internal/repository"
+)
+
+// DefaultListCostMultiplier is the assumed length of unpaginated lists
+const DefaultListCostMultiplier = 10
+
+// NewComplexityRoot prices list fields: connections cost their selection once
+// per requested item, unpaginated lists listMultiplier times. Other fields
+// cost 1 plus their selection.
+func NewComplexityRoot(listMultiplier int) generated.ComplexityRoot {
+	if listMultiplier <= 0 {
+		listMultiplier = DefaultListCostMultiplier
+	}
+	list := func(childComplexity int) int {
+		return 1 + childComplexity*listMultiplier
+	}
+
+	var root generated.ComplexityRoot
+	root.Query.Users = func(childComplexity int, first *int, after *string, last *int, before *string, filter *model.UserFilter, orderBy *model.UserOrderBy) int {
+		return 1 + childComplexity*pageSize(first, last)
+	}
+	root.Query.Restaurants = func(childComplexity int, first *int, after *string, last *int, before *string, filter *model.RestaurantFilter, orderBy *model.RestaurantOrderBy) int {
+		return 1 + childComplexity*pageSize(first, last)
+	}
+	root.User.Restaurants = list
+	root.Mutation.RestaurantsByUserID = func(childComplexity int, userID int) int {
+		return list(childComplexity)
+	}
+	return root
+}
+
+// pageSize mirrors the page size the repository will use; out of range values
+// are priced at the maximum and rejected by the repository
+func pageSize(first, last *int) int {
+	size := repository.DefaultPageSize
+	switch {
+	case first != nil:
+		size = *first
+	case last != nil:
+		size = *last
+	}
+	if size < 0 || size > repository.MaxPageSize {
+		return repository.MaxPageSize
+	}
+	return size
+}
@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/shennawardana23/graphql-pba/graph/model"
+	"github.com/shennawardana23/graphql-pba/internal/entity"
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+	"github.com/shennawardana23/graphql-pba/internal/util/helper"
+	"github.com/shennawardana23/graphql-pba/internal/util/i18n"
+)
+
+func newUserModel(user *entity.User) *model.User {
+	return &model.User{
+		ID:     int(user.ID),
+		Name:   user.Name,
+		Email:  user.Email,
+		Locale: helper.StringToPtr(user.Locale),
+	}
+}
+
+func newRestaurantModel(restaurant *entity.Restaurant) *model.Restaurant {
+	return &model.Restaurant{
+		ID:                 int(restaurant.ID),
+		UserID:             helper.Int64ToIntPtr(restaurant.UserID),
+		RestaurantName:     restaurant.RestaurantName,
+		RestaurantLogo:     restaurant.RestaurantLogo,
+		RestaurantFavicon:  restaurant.RestaurantFavicon,
+		ThumbnailDesktop:   restaurant.ThumbnailDesktop,
+		RestaurantPhone:    restaurant.RestaurantPhone,
+		RestaurantWhatsapp: restaurant.RestaurantWhatsapp,
+		RestaurantEmail:    restaurant.RestaurantEmail,
+		RestaurantAddress:  restaurant.RestaurantAddress,
+		RestaurantWebsite:  restaurant.RestaurantWebsite,
+	}
+}
+
+func newErrorCodeModel(ctx context.Context, d exception.Definition) *model.ErrorCode {
+	message, details := i18n.Error(ctx, d.Code, d.Message, d.Details)
+	return &model.ErrorCode{
+		Code:       d.Code,
+		Message:    message,
+		Details:    details,
+		HTTPStatus: d.HTTPStatus,
+		Severity:   model.ErrorSeverity(d.Severity),
+		Retryable:  d.Retryable,
+	}
+}
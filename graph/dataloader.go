@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/shennawardana23/graphql-pba/internal/dataloader"
+	"github.com/shennawardana23/graphql-pba/internal/entity"
+)
+
+type loadersKey struct{}
+
+// Loaders batch the relation lookups of a single request
+type Loaders struct {
+	UserByID            *dataloader.Loader[int64, *entity.User]
+	RestaurantsByUserID *dataloader.Loader[int64, []entity.Restaurant]
+}
+
+func NewLoaders(r *Resolver) *Loaders {
+	return &Loaders{
+		UserByID: dataloader.New(func(ctx context.Context, ids []int64) (map[int64]*entity.User, error) {
+			users, err := r.UserRepository.FindByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			result := make(map[int64]*entity.User, len(users))
+			for i := range users {
+				result[users[i].ID] = &users[i]
+			}
+			return result, nil
+		}),
+		RestaurantsByUserID: dataloader.New(func(ctx context.Context, userIDs []int64) (map[int64][]entity.Restaurant, error) {
+			restaurants, err := r.RestaurantRepository.FindByUserIDs(ctx, userIDs)
+			if err != nil {
+				return nil, err
+			}
+
+			result := make(map[int64][]entity.Restaurant, len(userIDs))
+			for _, restaurant := range restaurants {
+				if restaurant.UserID != nil {
+					result[*restaurant.UserID] = append(result[*restaurant.UserID], restaurant)
+				}
+			}
+			return result, nil
+		}),
+	}
+}
+
+// DataloaderMiddleware gives every request its own set of loaders. WebSocket
+// connections are skipped: they live for the whole subscription, and a cache
+// shared by all of its events would serve stale relations.
+func DataloaderMiddleware(r *Resolver, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if isWebsocketUpgrade(req) {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		ctx := context.WithValue(req.Context(), loadersKey{}, NewLoaders(r))
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// loadersFor returns the request loaders, or a fresh set when the middleware
+// was bypassed so resolvers never see a nil loader
+func loadersFor(ctx context.Context, r *Resolver) *Loaders {
+	if loaders, ok := ctx.Value(loadersKey{}).(*Loaders); ok {
+		return loaders
+	}
+	return NewLoaders(r)
+}
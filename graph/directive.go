@@ -0,0 +1,153 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/shennawardana23/graphql-pba/graph/generated"
+	"github.com/shennawardana23/graphql-pba/graph/model"
+	"github.com/shennawardana23/graphql-pba/internal/auth"
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+)
+
+// NewDirectiveRoot wires the schema authorization directives
+func NewDirectiveRoot(r *Resolver) generated.DirectiveRoot {
+	return generated.DirectiveRoot{
+		HasRole: hasRole,
+		IsOwner: r.isOwner,
+	}
+}
+
+func hasRole(ctx context.Context, obj interface{}, next graphql.Resolver, role model.Role) (interface{}, error) {
+	principal := auth.PrincipalFromContext(ctx)
+	if principal == nil {
+		return nil, unauthenticated(ctx)
+	}
+	if !principal.HasRole(role.String()) {
+		return nil, exception.ErrForbidden
+	}
+	return next(ctx)
+}
+
+// unauthenticated reports why an anonymous caller is turned away: the
+// rejected token when one was sent, TOKEN_EXPIRED telling the client to
+// refresh it
+func unauthenticated(ctx context.Context) error {
+	if err := auth.TokenErrorFromContext(ctx); err != nil {
+		return exception.TranslateTokenError(err)
+	}
+	return exception.ErrUnauthorized
+}
+
+func (r *Resolver) isOwner(ctx context.Context, obj interface{}, next graphql.Resolver, resource model.OwnedResource, idArg string) (interface{}, error) {
+	// The user of an AuthPayload is the caller signing in
+	if _, ok := obj.(*model.User); ok && parentObject(ctx) == "AuthPayload" {
+		return next(ctx)
+	}
+
+	principal := auth.PrincipalFromContext(ctx)
+	if principal == nil {
+		return nil, unauthenticated(ctx)
+	}
+
+	id, ok := ownedID(ctx, obj, idArg)
+	if !ok {
+		return nil, exception.ErrInvalidInput
+	}
+
+	var ownerID int64
+	switch resource {
+	case model.OwnedResourceUser:
+		ownerID = id
+	case model.OwnedResourceRestaurant:
+		restaurant, err := r.RestaurantRepository.FindByID(ctx, id)
+		if err != nil {
+			return nil, exception.ErrInternalServer.Wrap(err)
+		}
+		if restaurant == nil {
+			// Let the resolver report the missing record
+			return next(ctx)
+		}
+		// Restaurants without an owner are left to admins
+		if restaurant.UserID != nil {
+			ownerID = *restaurant.UserID
+		}
+	}
+
+	if !principal.CanActFor(ownerID) {
+		return nil, exception.ErrForbidden
+	}
+	return next(ctx)
+}
+
+// ownedID returns the id of the guarded resource: the user itself on User
+// fields, the argument at idArg otherwise
+func ownedID(ctx context.Context, obj interface{}, idArg string) (int64, bool) {
+	if user, ok := obj.(*model.User); ok {
+		return int64(user.ID), true
+	}
+	return argumentID(ctx, idArg)
+}
+
+// parentObject returns the type holding the object whose field is resolved,
+// e.g. "AuthPayload" for the fields of AuthPayload.user
+func parentObject(ctx context.Context) string {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Parent == nil {
+		return ""
+	}
+	return fc.Parent.Object
+}
+
+// argument returns the raw value at a dotted path such as "input.id" of the
+// field arguments, and whether it was given at all, even as null
+func argument(ctx context.Context, path string) (interface{}, bool) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Field.Field == nil {
+		return nil, false
+	}
+
+	var value interface{} = fc.Field.ArgumentMap(graphql.GetOperationContext(ctx).Variables)
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// argumentID reads the integer at a dotted path such as "input.id" from the
+// raw field arguments
+func argumentID(ctx context.Context, path string) (int64, bool) {
+	value, _ := argument(ctx, path)
+
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	case json.Number:
+		id, err := v.Int64()
+		return id, err == nil
+	case string:
+		id, err := strconv.ParseInt(v, 10, 64)
+		return id, err == nil
+	}
+	return 0, false
+}
+
+// argumentGiven reports whether the argument at a dotted path was sent, null
+// included
+func argumentGiven(ctx context.Context, path string) bool {
+	_, ok := argument(ctx, path)
+	return ok
+}
@@ -2,31 +2,118 @@ package graph
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+	"github.com/shennawardana23/graphql-pba/internal/util/i18n"
+	"github.com/shennawardana23/graphql-pba/internal/util/logger"
+	"github.com/shennawardana23/graphql-pba/internal/util/requestid"
+	"github.com/shennawardana23/graphql-pba/internal/util/validator"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
+// ErrorPresenter formats resolver errors and tags them with the request id
 func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
-	customErr, ok := err.(*exception.CustomError)
+	gqlErr := presentError(ctx, err)
+	if id := requestid.FromContext(ctx); id != "" {
+		if gqlErr.Extensions == nil {
+			gqlErr.Extensions = make(map[string]interface{})
+		}
+		gqlErr.Extensions["requestId"] = id
+	}
+	return gqlErr
+}
+
+// Recover turns a resolver panic into an internal error keeping the stack of
+// the panic
+func Recover(ctx context.Context, p interface{}) error {
+	err, ok := p.(error)
 	if !ok {
-		return &gqlerror.Error{
-			Message: "Validation failed",
+		err = fmt.Errorf("panic: %v", p)
+	} else {
+		err = fmt.Errorf("panic: %w", err)
+	}
+	return exception.ErrInternalServer.Wrap(err)
+}
+
+// presentError sorts errors into three classes:
+//   - GraphQL errors, raised by gqlgen while parsing, validating or coercing
+//     the request, are shown as is
+//   - domain errors, CustomErrors, are shown with their code and details
+//   - everything else is internal: masked, and logged with its cause and
+//     stack under an error id returned to the client
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	var customErr *exception.CustomError
+	if errors.As(err, &customErr) {
+		if customErr.Code == exception.CodeInternalServerError {
+			return presentInternalError(ctx, err, customErr.Stack())
+		}
+		message, details := i18n.Error(ctx, customErr.Code, customErr.Message, customErr.Details)
+		gqlErr := &gqlerror.Error{
+			Message: message,
 			Path:    graphql.GetPath(ctx),
 			Extensions: map[string]interface{}{
-				"code":    "VALIDATION_ERROR",
-				"details": err.Error(),
+				"code":    customErr.Code,
+				"details": details,
 			},
 		}
+		if len(customErr.FieldErrors) > 0 {
+			details, fields := validator.Localize(i18n.Locale(ctx), customErr.FieldErrors)
+			gqlErr.Extensions["details"] = details
+			gqlErr.Extensions["fieldErrors"] = fields
+		}
+		return gqlErr
 	}
 
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) {
+		// Parse, validation and protocol errors, such as
+		// PERSISTED_QUERY_NOT_FOUND, already carry a code clients depend on
+		if gqlErr.Extensions["code"] != nil {
+			return graphql.DefaultErrorPresenter(ctx, err)
+		}
+
+		// An argument that failed to unmarshal, e.g. a malformed Time
+		if gqlErr.Err != nil {
+			message, _ := i18n.Error(ctx, exception.ErrInvalidInput.Code, exception.ErrInvalidInput.Message, "")
+			return &gqlerror.Error{
+				Message: message,
+				Path:    gqlErr.Path,
+				Extensions: map[string]interface{}{
+					"code":    exception.ErrInvalidInput.Code,
+					"details": gqlErr.Message,
+				},
+			}
+		}
+	}
+
+	return presentInternalError(ctx, err, nil)
+}
+
+func presentInternalError(ctx context.Context, err error, stack []byte) *gqlerror.Error {
+	if stack == nil {
+		stack = debug.Stack()
+	}
+
+	errorID := requestid.New()
+	logger.FromContext(ctx).WithFields(map[string]interface{}{
+		"error_id": errorID,
+		"path":     graphql.GetPath(ctx).String(),
+		"cause":    err.Error(),
+		"stack":    string(stack),
+	}).Error("Internal error")
+
+	message, details := i18n.Error(ctx, exception.ErrInternalServer.Code, exception.ErrInternalServer.Message, exception.ErrInternalServer.Details)
 	return &gqlerror.Error{
-		Message: customErr.Message,
+		Message: message,
 		Path:    graphql.GetPath(ctx),
 		Extensions: map[string]interface{}{
-			"code":    customErr.Code,
-			"details": customErr.Details,
+			"code":    exception.ErrInternalServer.Code,
+			"details": details,
+			"errorId": errorID,
 		},
 	}
 }
@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"github.com/shennawardana23/graphql-pba/graph/model"
+	"github.com/shennawardana23/graphql-pba/internal/repository"
+)
+
+func restaurantFilter(f *model.RestaurantFilter) *repository.RestaurantFilter {
+	if f == nil {
+		return nil
+	}
+
+	filter := &repository.RestaurantFilter{
+		NameContains:  f.NameContains,
+		HasWebsite:    f.HasWebsite,
+		HasWhatsapp:   f.HasWhatsapp,
+		CreatedAfter:  f.CreatedAfter,
+		CreatedBefore: f.CreatedBefore,
+	}
+	if f.UserID != nil {
+		userID := int64(*f.UserID)
+		filter.UserID = &userID
+	}
+	return filter
+}
+
+func restaurantOrderBy(o *model.RestaurantOrderBy) repository.OrderBy {
+	if o == nil {
+		return repository.OrderBy{}
+	}
+	return repository.OrderBy{
+		Field: string(o.Field),
+		Desc:  o.Direction != nil && *o.Direction == model.SortDirectionDesc,
+	}
+}
+
+func userFilter(f *model.UserFilter) *repository.UserFilter {
+	if f == nil {
+		return nil
+	}
+
+	return &repository.UserFilter{
+		NameContains:  f.NameContains,
+		EmailContains: f.EmailContains,
+		CreatedAfter:  f.CreatedAfter,
+		CreatedBefore: f.CreatedBefore,
+	}
+}
+
+func userOrderBy(o *model.UserOrderBy) repository.OrderBy {
+	if o == nil {
+		return repository.OrderBy{}
+	}
+	return repository.OrderBy{
+		Field: string(o.Field),
+		Desc:  o.Direction != nil && *o.Direction == model.SortDirectionDesc,
+	}
+}
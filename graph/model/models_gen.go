@@ -2,7 +2,39 @@
 
 package model
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type AuthPayload struct {
+	AccessToken          string    `json:"accessToken"`
+	AccessTokenExpiresAt time.Time `json:"accessTokenExpiresAt"`
+	RefreshToken         string    `json:"refreshToken"`
+	User                 *User     `json:"user"`
+}
+
+// An error code clients may find in extensions.code
+type ErrorCode struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details"`
+	// HTTP status of the code when returned outside GraphQL
+	HTTPStatus int           `json:"httpStatus"`
+	Severity   ErrorSeverity `json:"severity"`
+	// Whether the same request may succeed later
+	Retryable bool `json:"retryable"`
+}
+
+type LoginInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
 type NewRestaurant struct {
+	// Owner, the caller when left out. Only admins may name another user or pass null for no owner.
 	UserID             *int    `json:"userId,omitempty"`
 	RestaurantName     string  `json:"restaurantName"`
 	RestaurantLogo     string  `json:"restaurantLogo"`
@@ -21,6 +53,19 @@ type NewUser struct {
 	Email string `json:"email"`
 }
 
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
+}
+
+type RegisterInput struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
 type Restaurant struct {
 	ID                 int     `json:"id"`
 	UserID             *int    `json:"userId,omitempty"`
@@ -36,6 +81,36 @@ type Restaurant struct {
 	User               *User   `json:"user,omitempty"`
 }
 
+type RestaurantChange struct {
+	Action     ChangeAction `json:"action"`
+	Restaurant *Restaurant  `json:"restaurant"`
+}
+
+type RestaurantConnection struct {
+	Edges      []*RestaurantEdge `json:"edges"`
+	PageInfo   *PageInfo         `json:"pageInfo"`
+	TotalCount int               `json:"totalCount"`
+}
+
+type RestaurantEdge struct {
+	Cursor string      `json:"cursor"`
+	Node   *Restaurant `json:"node"`
+}
+
+type RestaurantFilter struct {
+	NameContains  *string    `json:"nameContains,omitempty"`
+	HasWebsite    *bool      `json:"hasWebsite,omitempty"`
+	HasWhatsapp   *bool      `json:"hasWhatsapp,omitempty"`
+	UserID        *int       `json:"userId,omitempty"`
+	CreatedAfter  *time.Time `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"`
+}
+
+type RestaurantOrderBy struct {
+	Field     RestaurantOrderField `json:"field"`
+	Direction *SortDirection       `json:"direction,omitempty"`
+}
+
 type UpdateRestaurantInput struct {
 	ID                 int     `json:"id"`
 	UserID             *int    `json:"userId,omitempty"`
@@ -51,13 +126,341 @@ type UpdateRestaurantInput struct {
 }
 
 type UpdateUserInput struct {
-	ID    int     `json:"id"`
-	Name  *string `json:"name,omitempty"`
-	Email *string `json:"email,omitempty"`
+	ID     int     `json:"id"`
+	Name   *string `json:"name,omitempty"`
+	Email  *string `json:"email,omitempty"`
+	Locale *string `json:"locale,omitempty"`
 }
 
 type User struct {
-	ID    int    `json:"id"`
-	Name  string `json:"name"`
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Only shown to the user and admins
 	Email string `json:"email"`
+	// Preferred language of error messages, en or id. Overrides Accept-Language.
+	Locale      *string       `json:"locale,omitempty"`
+	Restaurants []*Restaurant `json:"restaurants"`
+}
+
+type UserChange struct {
+	Action ChangeAction `json:"action"`
+	User   *User        `json:"user"`
+}
+
+type UserConnection struct {
+	Edges      []*UserEdge `json:"edges"`
+	PageInfo   *PageInfo   `json:"pageInfo"`
+	TotalCount int         `json:"totalCount"`
+}
+
+type UserEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *User  `json:"node"`
+}
+
+type UserFilter struct {
+	NameContains  *string    `json:"nameContains,omitempty"`
+	EmailContains *string    `json:"emailContains,omitempty"`
+	CreatedAfter  *time.Time `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"`
+}
+
+type UserOrderBy struct {
+	Field     UserOrderField `json:"field"`
+	Direction *SortDirection `json:"direction,omitempty"`
+}
+
+type ChangeAction string
+
+const (
+	ChangeActionCreated ChangeAction = "CREATED"
+	ChangeActionUpdated ChangeAction = "UPDATED"
+	ChangeActionDeleted ChangeAction = "DELETED"
+)
+
+var AllChangeAction = []ChangeAction{
+	ChangeActionCreated,
+	ChangeActionUpdated,
+	ChangeActionDeleted,
+}
+
+func (e ChangeAction) IsValid() bool {
+	switch e {
+	case ChangeActionCreated, ChangeActionUpdated, ChangeActionDeleted:
+		return true
+	}
+	return false
+}
+
+func (e ChangeAction) String() string {
+	return string(e)
+}
+
+func (e *ChangeAction) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ChangeAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ChangeAction", str)
+	}
+	return nil
+}
+
+func (e ChangeAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ErrorSeverity string
+
+const (
+	ErrorSeverityInfo    ErrorSeverity = "INFO"
+	ErrorSeverityWarning ErrorSeverity = "WARNING"
+	ErrorSeverityError   ErrorSeverity = "ERROR"
+)
+
+var AllErrorSeverity = []ErrorSeverity{
+	ErrorSeverityInfo,
+	ErrorSeverityWarning,
+	ErrorSeverityError,
+}
+
+func (e ErrorSeverity) IsValid() bool {
+	switch e {
+	case ErrorSeverityInfo, ErrorSeverityWarning, ErrorSeverityError:
+		return true
+	}
+	return false
+}
+
+func (e ErrorSeverity) String() string {
+	return string(e)
+}
+
+func (e *ErrorSeverity) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ErrorSeverity(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ErrorSeverity", str)
+	}
+	return nil
+}
+
+func (e ErrorSeverity) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type OwnedResource string
+
+const (
+	OwnedResourceUser       OwnedResource = "USER"
+	OwnedResourceRestaurant OwnedResource = "RESTAURANT"
+)
+
+var AllOwnedResource = []OwnedResource{
+	OwnedResourceUser,
+	OwnedResourceRestaurant,
+}
+
+func (e OwnedResource) IsValid() bool {
+	switch e {
+	case OwnedResourceUser, OwnedResourceRestaurant:
+		return true
+	}
+	return false
+}
+
+func (e OwnedResource) String() string {
+	return string(e)
+}
+
+func (e *OwnedResource) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OwnedResource(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OwnedResource", str)
+	}
+	return nil
+}
+
+func (e OwnedResource) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type RestaurantOrderField string
+
+const (
+	RestaurantOrderFieldName      RestaurantOrderField = "NAME"
+	RestaurantOrderFieldCreatedAt RestaurantOrderField = "CREATED_AT"
+	RestaurantOrderFieldUpdatedAt RestaurantOrderField = "UPDATED_AT"
+)
+
+var AllRestaurantOrderField = []RestaurantOrderField{
+	RestaurantOrderFieldName,
+	RestaurantOrderFieldCreatedAt,
+	RestaurantOrderFieldUpdatedAt,
+}
+
+func (e RestaurantOrderField) IsValid() bool {
+	switch e {
+	case RestaurantOrderFieldName, RestaurantOrderFieldCreatedAt, RestaurantOrderFieldUpdatedAt:
+		return true
+	}
+	return false
+}
+
+func (e RestaurantOrderField) String() string {
+	return string(e)
+}
+
+func (e *RestaurantOrderField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RestaurantOrderField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RestaurantOrderField", str)
+	}
+	return nil
+}
+
+func (e RestaurantOrderField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Role string
+
+const (
+	RoleUser  Role = "USER"
+	RoleAdmin Role = "ADMIN"
+)
+
+var AllRole = []Role{
+	RoleUser,
+	RoleAdmin,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleUser, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+func (e *Role) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Role(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Role", str)
+	}
+	return nil
+}
+
+func (e Role) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SortDirection string
+
+const (
+	SortDirectionAsc  SortDirection = "ASC"
+	SortDirectionDesc SortDirection = "DESC"
+)
+
+var AllSortDirection = []SortDirection{
+	SortDirectionAsc,
+	SortDirectionDesc,
+}
+
+func (e SortDirection) IsValid() bool {
+	switch e {
+	case SortDirectionAsc, SortDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e SortDirection) String() string {
+	return string(e)
+}
+
+func (e *SortDirection) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortDirection", str)
+	}
+	return nil
+}
+
+func (e SortDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type UserOrderField string
+
+const (
+	UserOrderFieldName      UserOrderField = "NAME"
+	UserOrderFieldEmail     UserOrderField = "EMAIL"
+	UserOrderFieldCreatedAt UserOrderField = "CREATED_AT"
+)
+
+var AllUserOrderField = []UserOrderField{
+	UserOrderFieldName,
+	UserOrderFieldEmail,
+	UserOrderFieldCreatedAt,
+}
+
+func (e UserOrderField) IsValid() bool {
+	switch e {
+	case UserOrderFieldName, UserOrderFieldEmail, UserOrderFieldCreatedAt:
+		return true
+	}
+	return false
+}
+
+func (e UserOrderField) String() string {
+	return string(e)
+}
+
+func (e *UserOrderField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = UserOrderField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid UserOrderField", str)
+	}
+	return nil
+}
+
+func (e UserOrderField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
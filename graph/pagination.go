@@ -0,0 +1,16 @@
+package graph
+
+import "github.com/shennawardana23/graphql-pba/graph/model"
+
+// pageInfo builds the Relay page info from the cursors of the returned edges
+func pageInfo(cursors []string, hasNext, hasPrevious bool) *model.PageInfo {
+	info := &model.PageInfo{
+		HasNextPage:     hasNext,
+		HasPreviousPage: hasPrevious,
+	}
+	if len(cursors) > 0 {
+		info.StartCursor = &cursors[0]
+		info.EndCursor = &cursors[len(cursors)-1]
+	}
+	return info
+}
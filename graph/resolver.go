@@ -2,19 +2,27 @@ package graph
 
 import (
 	"github.com/go-pg/pg/v10"
+	"github.com/shennawardana23/graphql-pba/internal/auth"
+	"github.com/shennawardana23/graphql-pba/internal/event"
 	"github.com/shennawardana23/graphql-pba/internal/repository"
 )
 
 type Resolver struct {
-	DB                   *pg.DB
-	UserRepository       *repository.UserRepository
-	RestaurantRepository *repository.RestaurantRepository
+	DB                     *pg.DB
+	Tokens                 *auth.TokenService
+	Events                 *event.Bus
+	UserRepository         *repository.UserRepository
+	RestaurantRepository   *repository.RestaurantRepository
+	RefreshTokenRepository *repository.RefreshTokenRepository
 }
 
-func NewResolver(db *pg.DB) *Resolver {
+func NewResolver(db *pg.DB, tokens *auth.TokenService, events *event.Bus) *Resolver {
 	return &Resolver{
-		DB:                   db,
-		UserRepository:       repository.NewUserRepository(db),
-		RestaurantRepository: repository.NewRestaurantRepository(db),
+		DB:                     db,
+		Tokens:                 tokens,
+		Events:                 events,
+		UserRepository:         repository.NewUserRepository(db),
+		RestaurantRepository:   repository.NewRestaurantRepository(db),
+		RefreshTokenRepository: repository.NewRefreshTokenRepository(db),
 	}
 }
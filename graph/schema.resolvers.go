@@ -6,29 +6,137 @@ package graph
 
 import (
 	"context"
+	"time"
 
 	"github.com/shennawardana23/graphql-pba/graph/generated"
 	"github.com/shennawardana23/graphql-pba/graph/model"
+	"github.com/shennawardana23/graphql-pba/internal/auth"
 	"github.com/shennawardana23/graphql-pba/internal/entity"
+	"github.com/shennawardana23/graphql-pba/internal/event"
+	"github.com/shennawardana23/graphql-pba/internal/repository"
 	"github.com/shennawardana23/graphql-pba/internal/util/exception"
 	"github.com/shennawardana23/graphql-pba/internal/util/helper"
 	"github.com/shennawardana23/graphql-pba/internal/util/validation_model"
 	"github.com/shennawardana23/graphql-pba/internal/util/validator"
 )
 
+// Register is the resolver for the register field.
+func (r *mutationResolver) Register(ctx context.Context, input model.RegisterInput) (*model.AuthPayload, error) {
+	validationInput := validation_model.RegisterFromGQL(input)
+	v := validator.New()
+	if err := v.ValidateInput("input", validationInput); err != nil {
+		return nil, err
+	}
+
+	exists, err := r.UserRepository.ExistsByEmail(ctx, input.Email)
+	if err != nil {
+		return nil, exception.ErrInternalServer.Wrap(err)
+	}
+	if exists {
+		return nil, exception.ErrDuplicateEmail
+	}
+
+	passwordHash, err := auth.HashPassword(input.Password)
+	if err != nil {
+		return nil, exception.ErrInternalServer.Wrap(err)
+	}
+
+	user := &entity.User{
+		Name:         input.Name,
+		Email:        input.Email,
+		PasswordHash: passwordHash,
+		Role:         entity.RoleUser,
+	}
+
+	if err := r.UserRepository.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	r.Events.PublishUser(ctx, event.ActionCreated, *user)
+
+	return r.issueTokens(ctx, user)
+}
+
+// Login is the resolver for the login field.
+func (r *mutationResolver) Login(ctx context.Context, input model.LoginInput) (*model.AuthPayload, error) {
+	user, err := r.UserRepository.FindByEmail(ctx, input.Email)
+	if err != nil {
+		return nil, exception.ErrInternalServer.Wrap(err)
+	}
+
+	passwordHash := ""
+	if user != nil {
+		passwordHash = user.PasswordHash
+	}
+	if !auth.CheckPassword(passwordHash, input.Password) {
+		return nil, exception.ErrInvalidCredential
+	}
+
+	return r.issueTokens(ctx, user)
+}
+
+// RefreshToken is the resolver for the refreshToken field.
+func (r *mutationResolver) RefreshToken(ctx context.Context, refreshToken string) (*model.AuthPayload, error) {
+	token, err := r.RefreshTokenRepository.FindByHash(ctx, auth.HashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, exception.ErrInternalServer.Wrap(err)
+	}
+	if token == nil || time.Now().After(token.ExpiresAt) {
+		return nil, exception.TranslateTokenError(exception.ErrTokenInvalid)
+	}
+
+	// Refresh tokens are single use. Presenting a revoked one means it was
+	// leaked, so every session of that user is ended.
+	rotated, err := r.RefreshTokenRepository.Revoke(ctx, token.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		if err := r.RefreshTokenRepository.RevokeAllForUser(ctx, token.UserID); err != nil {
+			return nil, err
+		}
+		return nil, exception.TranslateTokenError(exception.ErrTokenInvalid)
+	}
+
+	user, err := r.UserRepository.FindByID(ctx, token.UserID)
+	if err != nil {
+		return nil, exception.ErrInternalServer.Wrap(err)
+	}
+	if user == nil {
+		return nil, exception.TranslateTokenError(exception.ErrTokenInvalid)
+	}
+
+	return r.issueTokens(ctx, user)
+}
+
+// Logout is the resolver for the logout field.
+func (r *mutationResolver) Logout(ctx context.Context, refreshToken string) (bool, error) {
+	token, err := r.RefreshTokenRepository.FindByHash(ctx, auth.HashRefreshToken(refreshToken))
+	if err != nil {
+		return false, exception.ErrInternalServer.Wrap(err)
+	}
+	if token == nil {
+		return false, exception.TranslateTokenError(exception.ErrTokenInvalid)
+	}
+
+	if _, err := r.RefreshTokenRepository.Revoke(ctx, token.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // CreateUser is the resolver for the createUser field.
 func (r *mutationResolver) CreateUser(ctx context.Context, input model.NewUser) (*model.User, error) {
 	// Convert to validation model and validate
 	validationInput := validation_model.NewUserFromGQL(input)
 	v := validator.New()
-	if err := v.ValidateStruct(validationInput); err != nil {
+	if err := v.ValidateInput("input", validationInput); err != nil {
 		return nil, err
 	}
 
 	// Check for duplicate email
 	exists, err := r.UserRepository.ExistsByEmail(ctx, input.Email)
 	if err != nil {
-		return nil, exception.ErrInternalServer
+		return nil, exception.ErrInternalServer.Wrap(err)
 	}
 	if exists {
 		return nil, exception.ErrDuplicateEmail
@@ -40,28 +148,26 @@ func (r *mutationResolver) CreateUser(ctx context.Context, input model.NewUser)
 	}
 
 	if err := r.UserRepository.Create(ctx, user); err != nil {
-		return nil, exception.ErrInternalServer
+		return nil, err
 	}
+	r.Events.PublishUser(ctx, event.ActionCreated, *user)
 
-	return &model.User{
-		ID:    int(user.ID),
-		Name:  user.Name,
-		Email: user.Email,
-	}, nil
+	return newUserModel(user), nil
 }
 
 // UpdateUser is the resolver for the updateUser field.
 func (r *mutationResolver) UpdateUser(ctx context.Context, input model.UpdateUserInput) (*model.User, error) {
-	// Validate input
+	// Convert to validation model and validate
+	validationInput := validation_model.UpdateUserFromGQL(input)
 	v := validator.New()
-	if err := v.ValidateStruct(input); err != nil {
+	if err := v.ValidateInput("input", validationInput); err != nil {
 		return nil, err
 	}
 
 	// Check if user exists
 	existingUser, err := r.UserRepository.FindByID(ctx, int64(input.ID))
 	if err != nil {
-		return nil, exception.ErrInternalServer
+		return nil, exception.ErrInternalServer.Wrap(err)
 	}
 	if existingUser == nil {
 		return nil, exception.ErrNotFound
@@ -71,7 +177,7 @@ func (r *mutationResolver) UpdateUser(ctx context.Context, input model.UpdateUse
 	if input.Email != nil && *input.Email != existingUser.Email {
 		exists, err := r.UserRepository.ExistsByEmail(ctx, *input.Email)
 		if err != nil {
-			return nil, exception.ErrInternalServer
+			return nil, exception.ErrInternalServer.Wrap(err)
 		}
 		if exists {
 			return nil, exception.ErrDuplicateEmail
@@ -85,33 +191,29 @@ func (r *mutationResolver) UpdateUser(ctx context.Context, input model.UpdateUse
 	if input.Email != nil {
 		existingUser.Email = *input.Email
 	}
+	if input.Locale != nil {
+		existingUser.Locale = *input.Locale
+	}
 
 	if err := r.UserRepository.Update(ctx, existingUser); err != nil {
-		return nil, exception.ErrInternalServer
+		return nil, err
 	}
+	r.Events.PublishUser(ctx, event.ActionUpdated, *existingUser)
 
-	return &model.User{
-		ID:    int(existingUser.ID),
-		Name:  existingUser.Name,
-		Email: existingUser.Email,
-	}, nil
+	return newUserModel(existingUser), nil
 }
 
 // DeleteUser is the resolver for the deleteUser field.
 func (r *mutationResolver) DeleteUser(ctx context.Context, id int) (*model.User, error) {
 	// Validate ID
 	if id <= 0 {
-		return nil, exception.NewCustomError(
-			"INVALID_ID",
-			"Invalid user ID",
-			"User ID must be a positive number",
-		)
+		return nil, exception.New(exception.CodeInvalidID)
 	}
 
 	// Check if user exists
 	existingUser, err := r.UserRepository.FindByID(ctx, int64(id))
 	if err != nil {
-		return nil, exception.ErrInternalServer
+		return nil, exception.ErrInternalServer.Wrap(err)
 	}
 	if existingUser == nil {
 		return nil, exception.ErrNotFound
@@ -119,51 +221,77 @@ func (r *mutationResolver) DeleteUser(ctx context.Context, id int) (*model.User,
 
 	// Delete user
 	if err := r.UserRepository.Delete(ctx, int64(id)); err != nil {
-		return nil, exception.ErrInternalServer
+		return nil, err
 	}
+	r.Events.PublishUser(ctx, event.ActionDeleted, *existingUser)
 
 	return &model.User{ID: id}, nil
 }
 
 // CreateRestaurant is the resolver for the createRestaurant field.
 func (r *mutationResolver) CreateRestaurant(ctx context.Context, input model.NewRestaurant) (*model.Restaurant, error) {
+	principal := auth.PrincipalFromContext(ctx)
+	userID := helper.IntToInt64Ptr(input.UserID)
+	switch {
+	case userID != nil:
+		// Only admins may create restaurants on behalf of another user
+		if !principal.CanActFor(*userID) {
+			return nil, exception.ErrForbidden
+		}
+	case argumentGiven(ctx, "input.userId"):
+		// An explicit null leaves the restaurant without an owner, which
+		// only admins can manage afterwards
+		if !principal.HasRole(entity.RoleAdmin) {
+			return nil, exception.ErrForbidden
+		}
+	default:
+		// Restaurants belong to their creator unless told otherwise
+		ownerID := principal.UserID
+		userID = &ownerID
+	}
+
+	// Convert to validation model and validate
+	validationInput := validation_model.NewRestaurantFromGQL(input)
+	v := validator.New()
+	if err := v.ValidateInput("input", validationInput); err != nil {
+		return nil, err
+	}
+	validationInput.Normalize()
+
 	restaurant := &entity.Restaurant{
-		UserID:             int64(*input.UserID),
-		RestaurantName:     input.RestaurantName,
-		RestaurantLogo:     input.RestaurantLogo,
-		RestaurantFavicon:  *input.RestaurantFavicon,
-		ThumbnailDesktop:   input.ThumbnailDesktop,
-		RestaurantPhone:    *input.RestaurantPhone,
-		RestaurantWhatsapp: *input.RestaurantWhatsapp,
-		RestaurantEmail:    *input.RestaurantEmail,
-		RestaurantAddress:  *input.RestaurantAddress,
-		RestaurantWebsite:  *input.RestaurantWebsite,
+		UserID:             userID,
+		RestaurantName:     validationInput.RestaurantName,
+		RestaurantLogo:     validationInput.RestaurantLogo,
+		RestaurantFavicon:  helper.StringToPtr(validationInput.RestaurantFavicon),
+		ThumbnailDesktop:   validationInput.ThumbnailDesktop,
+		RestaurantPhone:    helper.StringToPtr(validationInput.RestaurantPhone),
+		RestaurantWhatsapp: helper.StringToPtr(validationInput.RestaurantWhatsapp),
+		RestaurantEmail:    helper.StringToPtr(validationInput.RestaurantEmail),
+		RestaurantAddress:  helper.StringToPtr(validationInput.RestaurantAddress),
+		RestaurantWebsite:  helper.StringToPtr(validationInput.RestaurantWebsite),
 	}
 
 	if err := r.RestaurantRepository.Create(ctx, restaurant); err != nil {
-		return nil, exception.ErrInternalServer
-	}
-
-	return &model.Restaurant{
-		ID:                 int(restaurant.ID),
-		UserID:             helper.Int64ToIntPtr(&restaurant.UserID),
-		RestaurantName:     restaurant.RestaurantName,
-		RestaurantLogo:     restaurant.RestaurantLogo,
-		RestaurantFavicon:  &restaurant.RestaurantFavicon,
-		ThumbnailDesktop:   restaurant.ThumbnailDesktop,
-		RestaurantPhone:    &restaurant.RestaurantPhone,
-		RestaurantWhatsapp: &restaurant.RestaurantWhatsapp,
-		RestaurantEmail:    &restaurant.RestaurantEmail,
-		RestaurantAddress:  &restaurant.RestaurantAddress,
-		RestaurantWebsite:  &restaurant.RestaurantWebsite,
-	}, nil
+		return nil, err
+	}
+	r.Events.PublishRestaurant(ctx, event.ActionCreated, *restaurant)
+
+	return newRestaurantModel(restaurant), nil
 }
 
 // UpdateRestaurant is the resolver for the updateRestaurant field.
 func (r *mutationResolver) UpdateRestaurant(ctx context.Context, input model.UpdateRestaurantInput) (*model.Restaurant, error) {
+	// Convert to validation model and validate
+	validationInput := validation_model.UpdateRestaurantFromGQL(input)
+	v := validator.New()
+	if err := v.ValidateInput("input", validationInput); err != nil {
+		return nil, err
+	}
+	validationInput.Normalize()
+
 	restaurant, err := r.RestaurantRepository.FindByID(ctx, int64(input.ID))
 	if err != nil {
-		return nil, exception.ErrInternalServer
+		return nil, exception.ErrInternalServer.Wrap(err)
 	}
 	if restaurant == nil {
 		return nil, exception.ErrNotFound
@@ -171,201 +299,237 @@ func (r *mutationResolver) UpdateRestaurant(ctx context.Context, input model.Upd
 
 	// Update fields if provided
 	if input.UserID != nil {
-		restaurant.UserID = int64(*input.UserID)
+		// Ownership can only be handed to yourself unless you are an admin
+		if !auth.PrincipalFromContext(ctx).CanActFor(int64(*input.UserID)) {
+			return nil, exception.ErrForbidden
+		}
+		restaurant.UserID = helper.IntToInt64Ptr(input.UserID)
 	}
 	if input.RestaurantName != nil {
-		restaurant.RestaurantName = *input.RestaurantName
+		restaurant.RestaurantName = *validationInput.RestaurantName
 	}
 	if input.RestaurantLogo != nil {
-		restaurant.RestaurantLogo = *input.RestaurantLogo
+		restaurant.RestaurantLogo = *validationInput.RestaurantLogo
 	}
 	if input.RestaurantFavicon != nil {
-		restaurant.RestaurantFavicon = *input.RestaurantFavicon
+		restaurant.RestaurantFavicon = helper.StringToPtr(validationInput.RestaurantFavicon)
 	}
 	if input.ThumbnailDesktop != nil {
-		restaurant.ThumbnailDesktop = *input.ThumbnailDesktop
+		restaurant.ThumbnailDesktop = *validationInput.ThumbnailDesktop
 	}
 	if input.RestaurantPhone != nil {
-		restaurant.RestaurantPhone = *input.RestaurantPhone
+		restaurant.RestaurantPhone = helper.StringToPtr(validationInput.RestaurantPhone)
 	}
 	if input.RestaurantWhatsapp != nil {
-		restaurant.RestaurantWhatsapp = *input.RestaurantWhatsapp
+		restaurant.RestaurantWhatsapp = helper.StringToPtr(validationInput.RestaurantWhatsapp)
 	}
 	if input.RestaurantEmail != nil {
-		restaurant.RestaurantEmail = *input.RestaurantEmail
+		restaurant.RestaurantEmail = helper.StringToPtr(validationInput.RestaurantEmail)
 	}
 	if input.RestaurantAddress != nil {
-		restaurant.RestaurantAddress = *input.RestaurantAddress
+		restaurant.RestaurantAddress = helper.StringToPtr(validationInput.RestaurantAddress)
 	}
 	if input.RestaurantWebsite != nil {
-		restaurant.RestaurantWebsite = *input.RestaurantWebsite
+		restaurant.RestaurantWebsite = helper.StringToPtr(validationInput.RestaurantWebsite)
 	}
 
 	if err := r.RestaurantRepository.Update(ctx, restaurant); err != nil {
-		return nil, exception.ErrInternalServer
-	}
-
-	return &model.Restaurant{
-		ID:                 int(restaurant.ID),
-		UserID:             helper.Int64ToIntPtr(&restaurant.UserID),
-		RestaurantName:     restaurant.RestaurantName,
-		RestaurantLogo:     restaurant.RestaurantLogo,
-		RestaurantFavicon:  &restaurant.RestaurantFavicon,
-		ThumbnailDesktop:   restaurant.ThumbnailDesktop,
-		RestaurantPhone:    &restaurant.RestaurantPhone,
-		RestaurantWhatsapp: &restaurant.RestaurantWhatsapp,
-		RestaurantEmail:    &restaurant.RestaurantEmail,
-		RestaurantAddress:  &restaurant.RestaurantAddress,
-		RestaurantWebsite:  &restaurant.RestaurantWebsite,
-	}, nil
+		return nil, err
+	}
+	r.Events.PublishRestaurant(ctx, event.ActionUpdated, *restaurant)
+
+	return newRestaurantModel(restaurant), nil
 }
 
 // DeleteRestaurant is the resolver for the deleteRestaurant field.
 func (r *mutationResolver) DeleteRestaurant(ctx context.Context, id int) (*model.Restaurant, error) {
 	restaurant, err := r.RestaurantRepository.FindByID(ctx, int64(id))
 	if err != nil {
-		return nil, exception.ErrInternalServer
+		return nil, exception.ErrInternalServer.Wrap(err)
 	}
 	if restaurant == nil {
 		return nil, exception.ErrNotFound
 	}
 
 	if err := r.RestaurantRepository.Delete(ctx, int64(id)); err != nil {
-		return nil, exception.ErrInternalServer
+		return nil, err
 	}
+	r.Events.PublishRestaurant(ctx, event.ActionDeleted, *restaurant)
 
 	return &model.Restaurant{ID: id}, nil
 }
 
 // Mutation to get restaurants by user ID
 func (r *mutationResolver) RestaurantsByUserID(ctx context.Context, userID int) ([]*model.Restaurant, error) {
-	var restaurants []entity.Restaurant
-	err := r.RestaurantRepository.WithContext(ctx).
-		Model(&restaurants).
-		Relation("User").
-		Where("user_id = ?", userID).
-		Select()
+	restaurants, err := r.RestaurantRepository.FindByUserIDs(ctx, []int64{int64(userID)})
 	if err != nil {
-		return nil, exception.TranslatePostgresError(ctx, err)
-	}
-
-	var result []*model.Restaurant
-	for _, restaurant := range restaurants {
-		result = append(result, &model.Restaurant{
-			ID:                 int(restaurant.ID),
-			UserID:             helper.Int64ToIntPtr(&restaurant.UserID),
-			RestaurantName:     restaurant.RestaurantName,
-			RestaurantLogo:     restaurant.RestaurantLogo,
-			RestaurantFavicon:  &restaurant.RestaurantFavicon,
-			ThumbnailDesktop:   restaurant.ThumbnailDesktop,
-			RestaurantPhone:    &restaurant.RestaurantPhone,
-			RestaurantWhatsapp: &restaurant.RestaurantWhatsapp,
-			RestaurantEmail:    &restaurant.RestaurantEmail,
-			RestaurantAddress:  &restaurant.RestaurantAddress,
-			RestaurantWebsite:  &restaurant.RestaurantWebsite,
-			User: &model.User{
-				ID:    int(restaurant.User.ID),
-				Name:  restaurant.User.Name,
-				Email: restaurant.User.Email,
-			},
-		})
+		return nil, err
+	}
+
+	result := make([]*model.Restaurant, 0, len(restaurants))
+	for i := range restaurants {
+		result = append(result, newRestaurantModel(&restaurants[i]))
 	}
 	return result, nil
 }
 
 // Users is the resolver for the users field.
-func (r *queryResolver) Users(ctx context.Context) ([]*model.User, error) {
-	users, err := r.UserRepository.FindAll(ctx)
+func (r *queryResolver) Users(ctx context.Context, first *int, after *string, last *int, before *string, filter *model.UserFilter, orderBy *model.UserOrderBy) (*model.UserConnection, error) {
+	page, err := r.UserRepository.FindPage(ctx, repository.PageArgs{
+		First:  first,
+		After:  after,
+		Last:   last,
+		Before: before,
+	}, userFilter(filter), userOrderBy(orderBy))
 	if err != nil {
 		return nil, err
 	}
 
-	var result []*model.User
-	for _, u := range users {
-		result = append(result, &model.User{
-			ID:    int(u.ID),
-			Name:  u.Name,
-			Email: u.Email,
+	edges := make([]*model.UserEdge, 0, len(page.Items))
+	for i := range page.Items {
+		edges = append(edges, &model.UserEdge{
+			Cursor: page.Cursors[i],
+			Node:   newUserModel(&page.Items[i]),
 		})
 	}
-	return result, nil
+
+	return &model.UserConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo(page.Cursors, page.HasNextPage, page.HasPreviousPage),
+		TotalCount: page.TotalCount,
+	}, nil
 }
 
 // User is the resolver for the user field.
 func (r *queryResolver) User(ctx context.Context, id int) (*model.User, error) {
 	// Validate ID
 	if id <= 0 {
-		return nil, exception.NewCustomError(
-			"INVALID_ID",
-			"Invalid user ID",
-			"User ID must be a positive number",
-		)
+		return nil, exception.New(exception.CodeInvalidID)
 	}
 
 	user, err := r.UserRepository.FindByID(ctx, int64(id))
 	if err != nil {
-		return nil, exception.ErrInternalServer
+		return nil, exception.ErrInternalServer.Wrap(err)
 	}
 	if user == nil {
 		return nil, exception.ErrNotFound
 	}
 
-	return &model.User{
-		ID:    int(user.ID),
-		Name:  user.Name,
-		Email: user.Email,
-	}, nil
+	return newUserModel(user), nil
 }
 
 // Restaurants is the resolver for the restaurants field.
-func (r *queryResolver) Restaurants(ctx context.Context) ([]*model.Restaurant, error) {
-	restaurants, err := r.RestaurantRepository.FindAll(ctx)
+func (r *queryResolver) Restaurants(ctx context.Context, first *int, after *string, last *int, before *string, filter *model.RestaurantFilter, orderBy *model.RestaurantOrderBy) (*model.RestaurantConnection, error) {
+	page, err := r.RestaurantRepository.FindPage(ctx, repository.PageArgs{
+		First:  first,
+		After:  after,
+		Last:   last,
+		Before: before,
+	}, restaurantFilter(filter), restaurantOrderBy(orderBy))
 	if err != nil {
 		return nil, err
 	}
 
-	var result []*model.Restaurant
-	for _, r := range restaurants {
-		result = append(result, &model.Restaurant{
-			ID:                 int(r.ID),
-			UserID:             helper.Int64ToIntPtr(&r.UserID),
-			RestaurantName:     r.RestaurantName,
-			RestaurantLogo:     r.RestaurantLogo,
-			RestaurantFavicon:  &r.RestaurantFavicon,
-			ThumbnailDesktop:   r.ThumbnailDesktop,
-			RestaurantPhone:    &r.RestaurantPhone,
-			RestaurantWhatsapp: &r.RestaurantWhatsapp,
-			RestaurantEmail:    &r.RestaurantEmail,
-			RestaurantAddress:  &r.RestaurantAddress,
-			RestaurantWebsite:  &r.RestaurantWebsite,
+	edges := make([]*model.RestaurantEdge, 0, len(page.Items))
+	for i := range page.Items {
+		edges = append(edges, &model.RestaurantEdge{
+			Cursor: page.Cursors[i],
+			Node:   newRestaurantModel(&page.Items[i]),
 		})
 	}
-	return result, nil
+
+	return &model.RestaurantConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo(page.Cursors, page.HasNextPage, page.HasPreviousPage),
+		TotalCount: page.TotalCount,
+	}, nil
 }
 
 // Restaurant is the resolver for the restaurant field.
 func (r *queryResolver) Restaurant(ctx context.Context, id int) (*model.Restaurant, error) {
 	restaurant, err := r.RestaurantRepository.FindByID(ctx, int64(id))
 	if err != nil {
-		return nil, exception.ErrInternalServer
+		return nil, exception.ErrInternalServer.Wrap(err)
 	}
 	if restaurant == nil {
 		return nil, exception.ErrNotFound
 	}
 
-	return &model.Restaurant{
-		ID:                 int(restaurant.ID),
-		UserID:             helper.Int64ToIntPtr(&restaurant.UserID),
-		RestaurantName:     restaurant.RestaurantName,
-		RestaurantLogo:     restaurant.RestaurantLogo,
-		RestaurantFavicon:  &restaurant.RestaurantFavicon,
-		ThumbnailDesktop:   restaurant.ThumbnailDesktop,
-		RestaurantPhone:    &restaurant.RestaurantPhone,
-		RestaurantWhatsapp: &restaurant.RestaurantWhatsapp,
-		RestaurantEmail:    &restaurant.RestaurantEmail,
-		RestaurantAddress:  &restaurant.RestaurantAddress,
-		RestaurantWebsite:  &restaurant.RestaurantWebsite,
-	}, nil
+	return newRestaurantModel(restaurant), nil
+}
+
+// ErrorCodes is the resolver for the errorCodes field.
+func (r *queryResolver) ErrorCodes(ctx context.Context) ([]*model.ErrorCode, error) {
+	definitions := exception.Definitions()
+
+	codes := make([]*model.ErrorCode, 0, len(definitions))
+	for _, d := range definitions {
+		codes = append(codes, newErrorCodeModel(ctx, d))
+	}
+	return codes, nil
+}
+
+// User is the resolver for the user field.
+func (r *restaurantResolver) User(ctx context.Context, obj *model.Restaurant) (*model.User, error) {
+	if obj.User != nil {
+		return obj.User, nil
+	}
+	if obj.UserID == nil {
+		return nil, nil
+	}
+
+	user, err := loadersFor(ctx, r.Resolver).UserByID.Load(ctx, int64(*obj.UserID))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, nil
+	}
+	return newUserModel(user), nil
+}
+
+// RestaurantChanged is the resolver for the restaurantChanged field.
+func (r *subscriptionResolver) RestaurantChanged(ctx context.Context, userID *int) (<-chan *model.RestaurantChange, error) {
+	var filter func(event.RestaurantChanged) bool
+	if userID != nil {
+		ownerID := int64(*userID)
+		filter = func(e event.RestaurantChanged) bool {
+			return e.Restaurant.UserID != nil && *e.Restaurant.UserID == ownerID
+		}
+	}
+
+	events := r.Events.Restaurants.Subscribe(ctx, filter)
+	return forward(ctx, events, func(e event.RestaurantChanged) *model.RestaurantChange {
+		return &model.RestaurantChange{
+			Action:     model.ChangeAction(e.Action),
+			Restaurant: newRestaurantModel(&e.Restaurant),
+		}
+	}), nil
+}
+
+// UserChanged is the resolver for the userChanged field.
+func (r *subscriptionResolver) UserChanged(ctx context.Context, id int) (<-chan *model.UserChange, error) {
+	events := r.Events.Users.Subscribe(ctx, func(e event.UserChanged) bool { return e.User.ID == int64(id) })
+	return forward(ctx, events, func(e event.UserChanged) *model.UserChange {
+		return &model.UserChange{
+			Action: model.ChangeAction(e.Action),
+			User:   newUserModel(&e.User),
+		}
+	}), nil
+}
+
+// Restaurants is the resolver for the restaurants field.
+func (r *userResolver) Restaurants(ctx context.Context, obj *model.User) ([]*model.Restaurant, error) {
+	restaurants, err := loadersFor(ctx, r.Resolver).RestaurantsByUserID.Load(ctx, int64(obj.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Restaurant, 0, len(restaurants))
+	for i := range restaurants {
+		result = append(result, newRestaurantModel(&restaurants[i]))
+	}
+	return result, nil
 }
 
 // Mutation returns generated.MutationResolver implementation.
@@ -374,5 +538,17 @@ func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResol
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// Restaurant returns generated.RestaurantResolver implementation.
+func (r *Resolver) Restaurant() generated.RestaurantResolver { return &restaurantResolver{r} }
+
+// Subscription returns generated.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
+
+// User returns generated.UserResolver implementation.
+func (r *Resolver) User() generated.UserResolver { return &userResolver{r} }
+
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type restaurantResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
@@ -0,0 +1,29 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// forward converts subscription events into GraphQL models. The output is
+// unbuffered, so a client that reads slowly fills the subscriber buffer of the
+// topic until it is disconnected.
+func forward[E, M any](ctx context.Context, events <-chan E, convert func(E) M) <-chan M {
+	out := make(chan M)
+	go func() {
+		defer close(out)
+		for e := range events {
+			select {
+			case out <- convert(e):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func isWebsocketUpgrade(req *http.Request) bool {
+	return strings.EqualFold(req.Header.Get("Upgrade"), "websocket")
+}
@@ -0,0 +1,202 @@
+// Package accesslog writes one log line per GraphQL operation, the access log
+// entry of a POST /query saying little more than its status.
+package accesslog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/shennawardana23/graphql-pba/internal/app/database"
+	"github.com/shennawardana23/graphql-pba/internal/app/persistedquery"
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+	"github.com/shennawardana23/graphql-pba/internal/util/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	extensionName      = "AccessLog"
+	anonymousOperation = "anonymous"
+	redactedValue      = "[REDACTED]"
+)
+
+type Config struct {
+	// RedactFields are the variable and input field names, case insensitive,
+	// whose values are never logged
+	RedactFields []string
+}
+
+func NewConfig() *Config {
+	fields := getEnvOrDefault("ACCESS_LOG_REDACT_FIELDS", "email,emailContains,password,refreshToken,name,nameContains,restaurantEmail,restaurantPhone,restaurantWhatsapp,restaurantAddress")
+
+	config := &Config{}
+	for _, field := range strings.Split(fields, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			config.RedactFields = append(config.RedactFields, field)
+		}
+	}
+	return config
+}
+
+// AccessLog logs the name, type, document hash, redacted variables and
+// error codes of every query and mutation along with where its time went
+type AccessLog struct {
+	redact map[string]bool
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = &AccessLog{}
+
+func New(config *Config) *AccessLog {
+	redact := make(map[string]bool, len(config.RedactFields))
+	for _, field := range config.RedactFields {
+		redact[strings.ToLower(field)] = true
+	}
+	return &AccessLog{redact: redact}
+}
+
+func (l *AccessLog) ExtensionName() string {
+	return extensionName
+}
+
+func (l *AccessLog) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (l *AccessLog) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+
+	// Subscriptions get one response per event for as long as they live
+	oc := graphql.GetOperationContext(ctx)
+	if oc.Operation != nil && oc.Operation.Operation == ast.Subscription {
+		return next(ctx)
+	}
+
+	ctx, queries := database.WithQueryStats(ctx)
+	start := time.Now()
+	resp := next(ctx)
+	resolving := time.Since(start)
+
+	name, opType := operationLabels(oc)
+	fields := logrus.Fields{
+		"operation_name": name,
+		"operation_type": opType,
+		"document_hash":  persistedquery.Hash(oc.RawQuery),
+		"variables":      l.redactValue(oc.Variables),
+		"duration":       time.Since(oc.Stats.OperationStart).String(),
+		"parse_time":     elapsed(oc.Stats.Parsing),
+		"validate_time":  elapsed(oc.Stats.Validation),
+		"resolver_time":  resolving.String(),
+		"db_time":        queries.Duration().String(),
+		"db_queries":     queries.Count(),
+	}
+
+	var codes []string
+	if resp != nil {
+		for _, err := range resp.Errors {
+			code := "UNKNOWN"
+			if c, ok := err.Extensions["code"]; ok {
+				code = fmt.Sprint(c)
+			}
+			codes = append(codes, code)
+		}
+	}
+
+	entry := logger.FromContext(ctx).WithFields(fields)
+	if len(codes) > 0 {
+		entry.WithField("error_codes", codes).Log(logLevel(codes), "GraphQL operation failed")
+	} else {
+		entry.Info("GraphQL operation processed")
+	}
+
+	return resp
+}
+
+// logLevel picks the level of a failed operation from the most alarming
+// severity among its error codes, unregistered codes counting as warnings
+func logLevel(codes []string) logrus.Level {
+	level := logrus.InfoLevel
+	for _, code := range codes {
+		codeLevel := logrus.WarnLevel
+		if definition, ok := exception.Lookup(code); ok {
+			switch definition.Severity {
+			case exception.SeverityInfo:
+				codeLevel = logrus.InfoLevel
+			case exception.SeverityError:
+				codeLevel = logrus.ErrorLevel
+			}
+		}
+		// logrus orders levels from the most severe
+		if codeLevel < level {
+			level = codeLevel
+		}
+	}
+	return level
+}
+
+// redactValue copies value, replacing the values of redacted fields at any
+// depth
+func (l *AccessLog) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if l.redact[strings.ToLower(key)] && item != nil {
+				redacted[key] = redactedValue
+				continue
+			}
+			redacted[key] = l.redactValue(item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = l.redactValue(item)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// operationLabels returns the operation name, "anonymous" when unnamed, and
+// type
+func operationLabels(oc *graphql.OperationContext) (string, string) {
+	name := oc.OperationName
+	opType := string(ast.Query)
+	if oc.Operation != nil {
+		if name == "" {
+			name = oc.Operation.Name
+		}
+		if oc.Operation.Operation != "" {
+			opType = string(oc.Operation.Operation)
+		}
+	}
+	if name == "" {
+		name = anonymousOperation
+	}
+	return name, opType
+}
+
+// elapsed returns the duration of a phase, zero when it did not complete
+func elapsed(timing graphql.TraceTiming) string {
+	if timing.End.Before(timing.Start) {
+		return "0s"
+	}
+	return timing.End.Sub(timing.Start).String()
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/go-pg/pg/v10"
 	"github.com/shennawardana23/graphql-pba/internal/util/logger"
+	"github.com/shennawardana23/graphql-pba/migrations"
 )
 
 type DBConfig struct {
@@ -24,6 +25,15 @@ type DBConfig struct {
 	IdleTimeout     time.Duration
 	MaxRetries      int
 	MaxRetryBackoff time.Duration
+	ApplicationName string
+
+	Environment        string
+	SlowQueryThreshold time.Duration
+	QueryLogSampleRate float64
+	ExplainThreshold   time.Duration
+	// LogSafeColumns hold no personal data, the literals compared to them
+	// are logged as is; every other literal is redacted
+	LogSafeColumns string
 }
 
 func NewDBConfig() *DBConfig {
@@ -40,10 +50,42 @@ func NewDBConfig() *DBConfig {
 		IdleTimeout:     getEnvAsDuration("DB_IDLE_TIMEOUT", "5m"),
 		MaxRetries:      getEnvAsInt("DB_MAX_RETRIES", 3),
 		MaxRetryBackoff: getEnvAsDuration("DB_MAX_RETRY_BACKOFF", "5s"),
+		ApplicationName: getEnvOrDefault("DB_APPLICATION_NAME", "graphql-pba"),
+
+		Environment:        getEnvOrDefault("APP_ENV", "development"),
+		SlowQueryThreshold: getEnvAsDuration("DB_SLOW_QUERY_THRESHOLD", "200ms"),
+		QueryLogSampleRate: getEnvAsFloat("DB_QUERY_LOG_SAMPLE_RATE", 0.1),
+		ExplainThreshold:   getEnvAsDuration("DB_EXPLAIN_THRESHOLD", "0s"),
+		LogSafeColumns:     getEnvOrDefault("DB_LOG_SAFE_COLUMNS", "role,locale"),
 	}
 }
 
+// Connect opens the database for the API server and refuses to continue while
+// any embedded migration is still pending.
 func Connect() *pg.DB {
+	db := Open()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pending, err := NewMigrator(db, migrations.FS).Pending(ctx)
+	if err != nil {
+		panic("Failed to check database migrations: " + err.Error())
+	}
+	if len(pending) > 0 {
+		db.Close()
+		panic(fmt.Sprintf("Database has %d pending migration(s), run `make migrate-up` first", len(pending)))
+	}
+
+	// Export pool stats and query timings, and log them periodically
+	registerMetrics(db)
+	go monitorDBStats(db)
+
+	return db
+}
+
+// Open connects to the database without checking the migration state
+func Open() *pg.DB {
 	config := NewDBConfig()
 
 	opt := &pg.Options{
@@ -58,6 +100,8 @@ func Connect() *pg.DB {
 		IdleTimeout:     config.IdleTimeout,
 		MaxRetries:      config.MaxRetries,
 		MaxRetryBackoff: config.MaxRetryBackoff,
+		// Shown in pg_stat_activity next to the request_id comment of each query
+		ApplicationName: config.ApplicationName,
 
 		// Enable logging for slow queries
 		OnConnect: func(ctx context.Context, conn *pg.Conn) error {
@@ -69,7 +113,7 @@ func Connect() *pg.DB {
 	db := pg.Connect(opt)
 
 	// Add hooks for query monitoring
-	db.AddQueryHook(queryHook{})
+	db.AddQueryHook(queryHook{log: newQueryLogger(db, config)})
 
 	// Verify connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -79,30 +123,31 @@ func Connect() *pg.DB {
 		panic("Failed to connect to database: " + err.Error())
 	}
 
-	// Log database stats periodically
-	go monitorDBStats(db)
-
 	return db
 }
 
 // QueryHook for monitoring queries
-type queryHook struct{}
+type queryHook struct {
+	log *queryLogger
+}
 
 func (h queryHook) BeforeQuery(ctx context.Context, evt *pg.QueryEvent) (context.Context, error) {
 	return ctx, nil
 }
 
 func (h queryHook) AfterQuery(ctx context.Context, evt *pg.QueryEvent) error {
-	query, err := evt.FormattedQuery()
-	if err != nil {
-		return err
-	}
+	duration := time.Since(evt.StartTime)
 
-	logger.Log.WithFields(map[string]interface{}{
-		"query":    string(query),
-		"duration": time.Since(evt.StartTime),
-	}).Debug("Database query executed")
+	statement, table := "OTHER", "unknown"
+	if unformatted, err := evt.UnformattedQuery(); err == nil {
+		statement, table = classifyQuery(string(unformatted))
+	}
+	queryDuration.WithLabelValues(table, statement).Observe(duration.Seconds())
+	if stats := queryStatsFrom(ctx); stats != nil {
+		stats.add(duration)
+	}
 
+	h.log.log(ctx, evt, duration, statement, table)
 	return nil
 }
 
@@ -141,6 +186,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsDuration(key string, defaultValue string) time.Duration {
 	value := getEnvOrDefault(key, defaultValue)
 	duration, err := time.ParseDuration(value)
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	queryDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "Database query latency by table and statement kind",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		},
+		[]string{"table", "statement"},
+	)
+
+	queryTablePattern = regexp.MustCompile(`(?i)\b(?:FROM|INTO|UPDATE|JOIN)\s+"?([a-z_][a-z0-9_]*)"?`)
+	// leadingComment matches the request_id comment added by TracedDB
+	leadingComment = regexp.MustCompile(`^/\*.*?\*/\s*`)
+)
+
+// poolCollector reads the go-pg pool counters on every scrape
+type poolCollector struct {
+	db *pg.DB
+
+	totalConns *prometheus.Desc
+	idleConns  *prometheus.Desc
+	staleConns *prometheus.Desc
+	hits       *prometheus.Desc
+	misses     *prometheus.Desc
+	timeouts   *prometheus.Desc
+}
+
+func newPoolCollector(db *pg.DB) *poolCollector {
+	return &poolCollector{
+		db:         db,
+		totalConns: prometheus.NewDesc("db_pool_total_connections", "Number of connections in the pool", nil, nil),
+		idleConns:  prometheus.NewDesc("db_pool_idle_connections", "Number of idle connections in the pool", nil, nil),
+		staleConns: prometheus.NewDesc("db_pool_stale_connections_total", "Number of stale connections removed from the pool", nil, nil),
+		hits:       prometheus.NewDesc("db_pool_hits_total", "Number of times a free connection was found in the pool", nil, nil),
+		misses:     prometheus.NewDesc("db_pool_misses_total", "Number of times a free connection was not found in the pool", nil, nil),
+		timeouts:   prometheus.NewDesc("db_pool_timeouts_total", "Number of times a wait for a connection timed out", nil, nil),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.totalConns
+	ch <- c.idleConns
+	ch <- c.staleConns
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.timeouts
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.db.PoolStats()
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.staleConns, prometheus.CounterValue, float64(stats.StaleConns))
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.timeouts, prometheus.CounterValue, float64(stats.Timeouts))
+}
+
+// registerMetrics exposes the pool stats and query timings of db
+func registerMetrics(db *pg.DB) {
+	prometheus.MustRegister(newPoolCollector(db), queryDuration)
+}
+
+// classifyQuery returns the statement kind and the first table a query
+// touches, used as low-cardinality metric labels
+func classifyQuery(query string) (statement, table string) {
+	query = leadingComment.ReplaceAllString(strings.TrimSpace(query), "")
+
+	statement = "OTHER"
+	if i := strings.IndexFunc(query, func(r rune) bool { return r == ' ' || r == '\n' || r == '\t' || r == '(' }); i > 0 {
+		switch kind := strings.ToUpper(query[:i]); kind {
+		case "SELECT", "INSERT", "UPDATE", "DELETE":
+			statement = kind
+		case "WITH":
+			statement = "SELECT"
+		}
+	}
+
+	table = "unknown"
+	if match := queryTablePattern.FindStringSubmatch(query); match != nil {
+		table = strings.ToLower(match[1])
+	}
+	return statement, table
+}
@@ -0,0 +1,200 @@
+package database
+
+import (
+	"context"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/shennawardana23/graphql-pba/internal/util/logger"
+	"github.com/sirupsen/logrus"
+)
+
+const redactedValue = "'[REDACTED]'"
+
+var (
+	// The text before a literal compared to a column: "alias"."col" >= '
+	comparedColumn = regexp.MustCompile(`(?i)"?([a-z_][a-z0-9_]*)"?\s*(?:=|<>|!=|<=|>=|<|>|\bI?LIKE\b)\s*$`)
+	// The text before a literal listed for a column: "col" IN ('a', '
+	listedColumn = regexp.MustCompile(`(?i)"?([a-z_][a-z0-9_]*)"?\s+IN\s*\((?:\s*'(?:[^']|'')*'\s*,)*\s*$`)
+	// INSERT INTO "table" ("a", "b") VALUES (...), (...)
+	insertPattern = regexp.MustCompile(`(?is)^(INSERT\s+INTO\s+\S+(?:\s+AS\s+\S+)?\s*\(([^)]*)\)\s*VALUES\s*)`)
+)
+
+// maxPrefix bounds the text before a literal searched for its column
+const maxPrefix = 256
+
+type explainContextKey struct{}
+
+// queryLogger decides which executed queries are logged and how
+type queryLogger struct {
+	db               *pg.DB
+	slowThreshold    time.Duration
+	sampleRate       float64
+	explainThreshold time.Duration
+	safeColumns      map[string]bool
+}
+
+func newQueryLogger(db *pg.DB, config *DBConfig) *queryLogger {
+	columns := make(map[string]bool)
+	for _, column := range strings.Split(config.LogSafeColumns, ",") {
+		if column = strings.ToLower(strings.TrimSpace(column)); column != "" {
+			columns[column] = true
+		}
+	}
+
+	explainThreshold := config.ExplainThreshold
+	if config.Environment == "production" {
+		explainThreshold = 0
+	}
+
+	return &queryLogger{
+		db:               db,
+		slowThreshold:    config.SlowQueryThreshold,
+		sampleRate:       config.QueryLogSampleRate,
+		explainThreshold: explainThreshold,
+		safeColumns:      columns,
+	}
+}
+
+func (l *queryLogger) log(ctx context.Context, evt *pg.QueryEvent, duration time.Duration, statement, table string) {
+	// Skip the EXPLAIN queries issued by this logger
+	if ctx.Value(explainContextKey{}) != nil {
+		return
+	}
+
+	slow := l.slowThreshold > 0 && duration >= l.slowThreshold
+	if !slow && (l.sampleRate <= 0 || !logger.Log.IsLevelEnabled(logrus.DebugLevel) || rand.Float64() >= l.sampleRate) {
+		return
+	}
+
+	formatted, err := evt.FormattedQuery()
+	if err != nil {
+		return
+	}
+	query := l.redact(string(formatted))
+
+	entry := logger.FromContext(ctx).WithFields(map[string]interface{}{
+		"query":     query,
+		"duration":  duration,
+		"table":     table,
+		"statement": statement,
+	})
+	if !slow {
+		entry.Debug("Database query executed")
+		return
+	}
+	entry.Warn("Slow database query")
+
+	if l.explainThreshold > 0 && duration >= l.explainThreshold && statement == "SELECT" {
+		// EXPLAIN ANALYZE runs the statement again, so only read queries are
+		// explained, and off the request path
+		go l.explain(string(formatted), query, duration)
+	}
+}
+
+func (l *queryLogger) explain(formatted, redacted string, duration time.Duration) {
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), explainContextKey{}, true), 2*l.explainThreshold)
+	defer cancel()
+
+	var plan []string
+	if _, err := l.db.QueryContext(ctx, pg.Scan(&plan), "EXPLAIN (ANALYZE, BUFFERS) "+formatted); err != nil {
+		logger.Log.WithField("query", redacted).Warnf("Failed to explain slow query: %v", err)
+		return
+	}
+
+	logger.Log.WithFields(map[string]interface{}{
+		"query":    redacted,
+		"duration": duration,
+		"plan":     l.redact(strings.Join(plan, "\n")),
+	}).Warn("Slow database query plan")
+}
+
+// redact replaces every string literal with a placeholder, except the ones
+// compared to, listed for or inserted into a column known to hold no personal
+// data. Literals whose column cannot be told, such as the row values of a
+// keyset predicate, are redacted.
+func (l *queryLogger) redact(query string) string {
+	// Keep the request_id comment out of the statement
+	comment := leadingComment.FindString(query)
+	query = query[len(comment):]
+
+	var insertColumns []string
+	valuesStart := len(query)
+	if match := insertPattern.FindStringSubmatchIndex(query); match != nil {
+		insertColumns = strings.Split(query[match[4]:match[5]], ",")
+		valuesStart = match[1]
+	}
+
+	var out strings.Builder
+	out.WriteString(comment)
+	depth, position := 0, 0
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		if c != '\'' {
+			if i >= valuesStart {
+				switch {
+				case c == '(':
+					depth++
+					if depth == 1 {
+						position = 0
+					}
+				case c == ')':
+					depth--
+				case c == ',' && depth == 1:
+					position++
+				}
+			}
+			out.WriteByte(c)
+			i++
+			continue
+		}
+
+		end := literalEnd(query, i)
+		var column string
+		if i >= valuesStart && depth == 1 && position < len(insertColumns) {
+			column = strings.Trim(strings.TrimSpace(insertColumns[position]), `"`)
+		} else {
+			column = literalColumn(query[max(0, i-maxPrefix):i])
+		}
+
+		if l.safeColumns[strings.ToLower(column)] {
+			out.WriteString(query[i:end])
+		} else {
+			out.WriteString(redactedValue)
+		}
+		i = end
+	}
+	return out.String()
+}
+
+// literalColumn returns the column a literal following prefix is compared to
+// or listed for, "" when there is none
+func literalColumn(prefix string) string {
+	if match := comparedColumn.FindStringSubmatch(prefix); match != nil {
+		return match[1]
+	}
+	if match := listedColumn.FindStringSubmatch(prefix); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// literalEnd returns the index following the literal starting at start,
+// the end of query for an unterminated one
+func literalEnd(query string, start int) int {
+	for i := start + 1; i < len(query); i++ {
+		if query[i] != '\'' {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == '\'' {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(query)
+}
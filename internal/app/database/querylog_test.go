@@ -0,0 +1,134 @@
+package database
+
+import "testing"
+
+func TestQueryLoggerRedact(t *testing.T) {
+	l := newQueryLogger(nil, &DBConfig{LogSafeColumns: " Role, locale ,"})
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			"column off the allow-list",
+			`SELECT * FROM "users" WHERE "email" = 'ann@example.com'`,
+			`SELECT * FROM "users" WHERE "email" = '[REDACTED]'`,
+		},
+		{
+			"column on the allow-list",
+			`SELECT * FROM "users" WHERE "role" = 'ADMIN'`,
+			`SELECT * FROM "users" WHERE "role" = 'ADMIN'`,
+		},
+		{
+			"allow-list is case insensitive",
+			`SELECT * FROM users WHERE ROLE <> 'ADMIN' AND "user"."Locale" != 'id'`,
+			`SELECT * FROM users WHERE ROLE <> 'ADMIN' AND "user"."Locale" != 'id'`,
+		},
+		{
+			"escaped quotes stay inside the literal",
+			`SELECT * FROM users WHERE name = 'O''Brien' AND role = 'USER'`,
+			`SELECT * FROM users WHERE name = '[REDACTED]' AND role = 'USER'`,
+		},
+		{
+			"literal text resembling a safe comparison",
+			`SELECT * FROM users WHERE name = 'x'' OR role = ''ADMIN' AND locale = 'en'`,
+			`SELECT * FROM users WHERE name = '[REDACTED]' AND locale = 'en'`,
+		},
+		{
+			"pattern match",
+			`SELECT * FROM users WHERE email ILIKE '%@example.com' OR role LIKE 'AD%'`,
+			`SELECT * FROM users WHERE email ILIKE '[REDACTED]' OR role LIKE 'AD%'`,
+		},
+		{
+			"IN list of a safe column",
+			`SELECT * FROM users WHERE "role" IN ('USER', 'ADMIN')`,
+			`SELECT * FROM users WHERE "role" IN ('USER', 'ADMIN')`,
+		},
+		{
+			"IN list of another column",
+			`SELECT * FROM users WHERE "email" IN ('a@example.com', 'it''s@example.com', 'c@example.com')`,
+			`SELECT * FROM users WHERE "email" IN ('[REDACTED]', '[REDACTED]', '[REDACTED]')`,
+		},
+		{
+			"keyset row values",
+			`SELECT * FROM users WHERE ("created_at", "id") < ('2024-01-01 00:00:00+00', 5)`,
+			`SELECT * FROM users WHERE ("created_at", "id") < ('[REDACTED]', 5)`,
+		},
+		{
+			"INSERT values by column position",
+			`INSERT INTO "users" ("name", "email", "role") VALUES ('Ann', 'ann@example.com', 'USER'), ('Bob', 'bob@example.com', 'ADMIN')`,
+			`INSERT INTO "users" ("name", "email", "role") VALUES ('[REDACTED]', '[REDACTED]', 'USER'), ('[REDACTED]', '[REDACTED]', 'ADMIN')`,
+		},
+		{
+			"INSERT value inside a function call",
+			`INSERT INTO "users" AS "user" ("email", "role", "locale") VALUES (lower('Ann@Example.com'), 'USER', DEFAULT) RETURNING "id"`,
+			`INSERT INTO "users" AS "user" ("email", "role", "locale") VALUES (lower('[REDACTED]'), 'USER', DEFAULT) RETURNING "id"`,
+		},
+		{
+			"request id comment",
+			`/* request_id='abc' */ SELECT * FROM users WHERE email = 'ann@example.com'`,
+			`/* request_id='abc' */ SELECT * FROM users WHERE email = '[REDACTED]'`,
+		},
+		{
+			"unterminated literal",
+			`SELECT * FROM users WHERE email = 'ann@example.com`,
+			`SELECT * FROM users WHERE email = '[REDACTED]'`,
+		},
+		{
+			"literal without a column",
+			`SELECT 'ann@example.com' AS email`,
+			`SELECT '[REDACTED]' AS email`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l.redact(tt.query); got != tt.want {
+				t.Errorf("redact(%q)\n got %q\nwant %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLiteralColumn(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{`WHERE "role" = `, "role"},
+		{`WHERE "user"."role">=`, "role"},
+		{`WHERE name ILIKE `, "name"},
+		{`WHERE role IN (`, "role"},
+		{`WHERE role IN ('USER', `, "role"},
+		{`WHERE role IN ('it''s', `, "role"},
+		{`WHERE ("created_at", "id") < (`, ""},
+		{`SELECT `, ""},
+	}
+
+	for _, tt := range tests {
+		if got := literalColumn(tt.prefix); got != tt.want {
+			t.Errorf("literalColumn(%q) = %q, want %q", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestLiteralEnd(t *testing.T) {
+	tests := []struct {
+		query string
+		start int
+		want  int
+	}{
+		{`'abc' x`, 0, 5},
+		{`x = 'it''s' y`, 4, 11},
+		{`''`, 0, 2},
+		{`''''`, 0, 4},
+		{`'abc`, 0, 4},
+	}
+
+	for _, tt := range tests {
+		if got := literalEnd(tt.query, tt.start); got != tt.want {
+			t.Errorf("literalEnd(%q, %d) = %d, want %d", tt.query, tt.start, got, tt.want)
+		}
+	}
+}
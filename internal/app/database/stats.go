@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+type queryStatsKey struct{}
+
+// QueryStats sums the queries executed with a context carrying it, including
+// those of dataloader batches started from that context
+type QueryStats struct {
+	count    atomic.Int64
+	duration atomic.Int64
+}
+
+// WithQueryStats returns a context recording its queries into the returned
+// stats
+func WithQueryStats(ctx context.Context) (context.Context, *QueryStats) {
+	stats := &QueryStats{}
+	return context.WithValue(ctx, queryStatsKey{}, stats), stats
+}
+
+// Count returns the number of queries executed
+func (s *QueryStats) Count() int64 {
+	return s.count.Load()
+}
+
+// Duration returns the time spent in the database. Queries running
+// concurrently are all counted, so it can exceed the wall time.
+func (s *QueryStats) Duration() time.Duration {
+	return time.Duration(s.duration.Load())
+}
+
+func (s *QueryStats) add(duration time.Duration) {
+	s.count.Add(1)
+	s.duration.Add(int64(duration))
+}
+
+func queryStatsFrom(ctx context.Context) *QueryStats {
+	stats, _ := ctx.Value(queryStatsKey{}).(*QueryStats)
+	return stats
+}
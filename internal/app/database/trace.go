@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/shennawardana23/graphql-pba/internal/util/requestid"
+)
+
+// TracedDB runs every statement with a leading /* request_id=... */ comment,
+// so a query seen in pg_stat_activity or the Postgres logs can be traced back
+// to the request that sent it
+type TracedDB struct {
+	*pg.DB
+	comment string
+}
+
+var _ orm.DB = &TracedDB{}
+
+// Trace binds db to ctx like pg.DB.WithContext and tags its statements with
+// the request id of ctx
+func Trace(ctx context.Context, db *pg.DB) *TracedDB {
+	t := &TracedDB{DB: db.WithContext(ctx)}
+	// Ids are validated by requestid, they cannot close the comment
+	if id := requestid.FromContext(ctx); id != "" {
+		t.comment = "/* request_id=" + id + " */ "
+	}
+	return t
+}
+
+func (t *TracedDB) Model(model ...interface{}) *orm.Query {
+	return orm.NewQuery(t, model...)
+}
+
+func (t *TracedDB) ModelContext(c context.Context, model ...interface{}) *orm.Query {
+	return orm.NewQueryContext(c, t, model...)
+}
+
+func (t *TracedDB) Exec(query interface{}, params ...interface{}) (pg.Result, error) {
+	return t.DB.Exec(t.tag(query), params...)
+}
+
+func (t *TracedDB) ExecContext(c context.Context, query interface{}, params ...interface{}) (pg.Result, error) {
+	return t.DB.ExecContext(c, t.tag(query), params...)
+}
+
+func (t *TracedDB) ExecOne(query interface{}, params ...interface{}) (pg.Result, error) {
+	return t.DB.ExecOne(t.tag(query), params...)
+}
+
+func (t *TracedDB) ExecOneContext(c context.Context, query interface{}, params ...interface{}) (pg.Result, error) {
+	return t.DB.ExecOneContext(c, t.tag(query), params...)
+}
+
+func (t *TracedDB) Query(model, query interface{}, params ...interface{}) (pg.Result, error) {
+	return t.DB.Query(model, t.tag(query), params...)
+}
+
+func (t *TracedDB) QueryContext(c context.Context, model, query interface{}, params ...interface{}) (pg.Result, error) {
+	return t.DB.QueryContext(c, model, t.tag(query), params...)
+}
+
+func (t *TracedDB) QueryOne(model, query interface{}, params ...interface{}) (pg.Result, error) {
+	return t.DB.QueryOne(model, t.tag(query), params...)
+}
+
+func (t *TracedDB) QueryOneContext(c context.Context, model, query interface{}, params ...interface{}) (pg.Result, error) {
+	return t.DB.QueryOneContext(c, model, t.tag(query), params...)
+}
+
+func (t *TracedDB) tag(query interface{}) interface{} {
+	if t.comment == "" {
+		return query
+	}
+	switch q := query.(type) {
+	case string:
+		return t.comment + q
+	case orm.QueryCommand:
+		return commentedQuery{QueryCommand: q, comment: t.comment}
+	}
+	return query
+}
+
+// commentedQuery prefixes the SQL of an ORM query, formatted or not, with the
+// trace comment
+type commentedQuery struct {
+	orm.QueryCommand
+	comment string
+}
+
+func (q commentedQuery) AppendQuery(fmter orm.QueryFormatter, b []byte) ([]byte, error) {
+	return q.QueryCommand.AppendQuery(fmter, append(b, q.comment...))
+}
+
+func (q commentedQuery) AppendTemplate(b []byte) ([]byte, error) {
+	return q.QueryCommand.AppendTemplate(append(b, q.comment...))
+}
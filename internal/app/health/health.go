@@ -0,0 +1,143 @@
+// Package health serves the liveness and readiness probes.
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-pg/pg/v10"
+	"github.com/shennawardana23/graphql-pba/internal/util/logger"
+)
+
+const (
+	StatusPass = "pass"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+
+	checkTimeout = 2 * time.Second
+
+	// poolSaturationWarn is the share of busy pool connections reported as a warning
+	poolSaturationWarn = 0.8
+)
+
+type CheckResult struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Message string `json:"message,omitempty"`
+}
+
+type Response struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type Checker struct {
+	db       *pg.DB
+	started  time.Time
+	draining atomic.Bool
+}
+
+func NewChecker(db *pg.DB) *Checker {
+	return &Checker{db: db, started: time.Now()}
+}
+
+// SetDraining makes readiness fail so load balancers stop routing new
+// requests while in-flight ones finish
+func (h *Checker) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Liveness reports that the process is up and serving HTTP
+func (h *Checker) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, Response{
+		Status: StatusPass,
+		Checks: map[string]CheckResult{
+			"process": {
+				Status:  StatusPass,
+				Latency: "0s",
+				Message: "up for " + time.Since(h.started).Round(time.Second).String(),
+			},
+		},
+	})
+}
+
+// Readiness reports whether the instance can serve traffic. The probe is
+// public, so failures are logged and reported with a generic message.
+func (h *Checker) Readiness(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), checkTimeout)
+	defer cancel()
+
+	checks := map[string]CheckResult{
+		"draining":          h.checkDraining(),
+		"database":          timed(func() (string, string) { return h.checkPing(ctx) }),
+		"table_users":       timed(func() (string, string) { return h.checkTable(ctx, "users") }),
+		"table_restaurants": timed(func() (string, string) { return h.checkTable(ctx, "restaurants") }),
+		"database_pool":     timed(h.checkPool),
+	}
+
+	status, code := StatusPass, http.StatusOK
+	for _, check := range checks {
+		if check.Status == StatusFail {
+			status, code = StatusFail, http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	c.JSON(code, Response{Status: status, Checks: checks})
+}
+
+func (h *Checker) checkDraining() CheckResult {
+	if h.draining.Load() {
+		return CheckResult{Status: StatusFail, Latency: "0s", Message: "shutting down"}
+	}
+	return CheckResult{Status: StatusPass, Latency: "0s"}
+}
+
+func (h *Checker) checkPing(ctx context.Context) (string, string) {
+	if err := h.db.Ping(ctx); err != nil {
+		logger.FromContext(ctx).WithError(err).Warn("Readiness check: database ping failed")
+		return StatusFail, "database unavailable"
+	}
+	return StatusPass, ""
+}
+
+func (h *Checker) checkTable(ctx context.Context, table string) (string, string) {
+	var one int
+	_, err := h.db.WithContext(ctx).QueryOneContext(ctx, pg.Scan(&one), "SELECT 1 FROM ? LIMIT 1", pg.Ident(table))
+	if err != nil && err != pg.ErrNoRows {
+		logger.FromContext(ctx).WithError(err).WithField("table", table).Warn("Readiness check: table query failed")
+		return StatusFail, "table unavailable"
+	}
+	return StatusPass, ""
+}
+
+func (h *Checker) checkPool() (string, string) {
+	stats := h.db.PoolStats()
+	size := h.db.Options().PoolSize
+	if size <= 0 {
+		return StatusPass, ""
+	}
+
+	busy := int(stats.TotalConns) - int(stats.IdleConns)
+	saturation := float64(busy) / float64(size)
+	message := fmt.Sprintf("%d/%d connections in use, %d timeouts", busy, size, stats.Timeouts)
+
+	if saturation >= poolSaturationWarn {
+		return StatusWarn, message
+	}
+	return StatusPass, message
+}
+
+func timed(check func() (string, string)) CheckResult {
+	start := time.Now()
+	status, message := check()
+	return CheckResult{
+		Status:  status,
+		Latency: time.Since(start).String(),
+		Message: message,
+	}
+}
@@ -0,0 +1,154 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	anonymousOperation = "anonymous"
+	// otherOperation labels the operations whose name is not known upfront
+	otherOperation = "other"
+)
+
+// GraphQLMetrics is a gqlgen extension recording per-operation request counts,
+// latency and error codes, and optionally the latency of every resolver.
+type GraphQLMetrics struct {
+	fieldLatency bool
+	operations   map[string]bool
+
+	requests          *prometheus.CounterVec
+	operationDuration *prometheus.HistogramVec
+	errors            *prometheus.CounterVec
+	fieldDuration     *prometheus.HistogramVec
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = &GraphQLMetrics{}
+
+// NewGraphQLMetrics registers the collectors on reg. Field latency adds one
+// series per resolver field, so it is opt-in. Clients name operations freely,
+// so only the names in operations become label values, any other is counted
+// as "other".
+func NewGraphQLMetrics(reg prometheus.Registerer, fieldLatency bool, operations []string) *GraphQLMetrics {
+	known := make(map[string]bool, len(operations))
+	for _, name := range operations {
+		known[name] = true
+	}
+
+	m := &GraphQLMetrics{
+		fieldLatency: fieldLatency,
+		operations:   known,
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "graphql_requests_total",
+				Help: "Total number of GraphQL operations",
+			},
+			[]string{"operation", "operation_type"},
+		),
+		operationDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "graphql_request_duration_seconds",
+				Help:    "GraphQL operation latency",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"operation", "operation_type"},
+		),
+		errors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "graphql_errors_total",
+				Help: "Total number of GraphQL errors by extensions.code",
+			},
+			[]string{"operation", "operation_type", "code"},
+		),
+		fieldDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "graphql_field_duration_seconds",
+				Help:    "GraphQL resolver latency",
+				Buckets: []float64{.0005, .001, .005, .01, .025, .05, .1, .25, .5, 1},
+			},
+			[]string{"object", "field"},
+		),
+	}
+
+	reg.MustRegister(m.requests, m.operationDuration, m.errors)
+	if fieldLatency {
+		reg.MustRegister(m.fieldDuration)
+	}
+	return m
+}
+
+func (m *GraphQLMetrics) ExtensionName() string {
+	return "PrometheusMetrics"
+}
+
+func (m *GraphQLMetrics) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (m *GraphQLMetrics) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+	if !graphql.HasOperationContext(ctx) {
+		return resp
+	}
+
+	oc := graphql.GetOperationContext(ctx)
+	name, opType := operationLabels(oc)
+	if name != anonymousOperation && !m.operations[name] {
+		name = otherOperation
+	}
+
+	m.requests.WithLabelValues(name, opType).Inc()
+	m.operationDuration.WithLabelValues(name, opType).Observe(time.Since(oc.Stats.OperationStart).Seconds())
+
+	if resp != nil {
+		for _, err := range resp.Errors {
+			code := "UNKNOWN"
+			if c, ok := err.Extensions["code"]; ok {
+				code = fmt.Sprint(c)
+			}
+			m.errors.WithLabelValues(name, opType, code).Inc()
+		}
+	}
+
+	return resp
+}
+
+func (m *GraphQLMetrics) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if !m.fieldLatency || fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	start := time.Now()
+	res, err := next(ctx)
+	m.fieldDuration.WithLabelValues(fc.Object, fc.Field.Name).Observe(time.Since(start).Seconds())
+	return res, err
+}
+
+// operationLabels returns the operation name and type used as metric labels
+func operationLabels(oc *graphql.OperationContext) (string, string) {
+	name := oc.OperationName
+	opType := "unknown"
+	if oc.Operation != nil {
+		if name == "" {
+			name = oc.Operation.Name
+		}
+		opType = string(oc.Operation.Operation)
+	}
+	if name == "" {
+		name = anonymousOperation
+	}
+	if opType == "" {
+		opType = string(ast.Query)
+	}
+	return name, opType
+}
@@ -0,0 +1,112 @@
+package persistedquery
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Allowlist only executes the operations of a manifest. Clients send the
+// sha256 hash in the APQ extension, or the full document when it is listed;
+// any other document is rejected.
+type Allowlist struct {
+	documents map[string]string
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationParameterMutator
+} = &Allowlist{}
+
+// LoadAllowlist reads a JSON manifest mapping sha256 hashes to documents,
+// as produced by persisted query tooling: {"<sha256>": "query { ... }"}
+func LoadAllowlist(path string) (*Allowlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents map[string]string
+	if err := json.Unmarshal(data, &documents); err != nil {
+		return nil, fmt.Errorf("parse manifest %s: %w", path, err)
+	}
+	for hash, query := range documents {
+		if Hash(query) != hash {
+			return nil, fmt.Errorf("manifest %s: hash %s does not match its document", path, hash)
+		}
+	}
+
+	return &Allowlist{documents: documents}, nil
+}
+
+func (a *Allowlist) Len() int {
+	return len(a.documents)
+}
+
+// OperationNames returns the names of the operations in the manifest, the
+// only ones clients can run
+func (a *Allowlist) OperationNames() []string {
+	var names []string
+	for _, query := range a.documents {
+		doc, err := parser.ParseQuery(&ast.Source{Input: query})
+		if err != nil {
+			continue
+		}
+		for _, op := range doc.Operations {
+			if op.Name != "" {
+				names = append(names, op.Name)
+			}
+		}
+	}
+	return names
+}
+
+func (a *Allowlist) ExtensionName() string {
+	return "TrustedDocuments"
+}
+
+func (a *Allowlist) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (a *Allowlist) MutateOperationParameters(ctx context.Context, rawParams *graphql.RawParams) *gqlerror.Error {
+	hash := persistedQueryHash(rawParams.Extensions)
+	if hash == "" && rawParams.Query != "" {
+		hash = Hash(rawParams.Query)
+	}
+
+	query, ok := a.documents[hash]
+	if !ok || (rawParams.Query != "" && rawParams.Query != query) {
+		return &gqlerror.Error{
+			Message: exception.ErrPersistedQueryNotAllowed.Message,
+			Err:     exception.ErrPersistedQueryNotAllowed,
+		}
+	}
+
+	rawParams.Query = query
+	return nil
+}
+
+// Hash returns the hex sha256 of a document, as used by APQ
+func Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+func persistedQueryHash(extensions map[string]interface{}) string {
+	persisted, ok := extensions["persistedQuery"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	hash, _ := persisted["sha256Hash"].(string)
+	return hash
+}
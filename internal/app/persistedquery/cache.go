@@ -0,0 +1,184 @@
+// Package persistedquery resolves GraphQL documents from their sha256 hash,
+// either learned through automatic persisted queries or from an allowlist.
+package persistedquery
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/shennawardana23/graphql-pba/internal/entity"
+	"github.com/shennawardana23/graphql-pba/internal/repository"
+	"github.com/shennawardana23/graphql-pba/internal/util/logger"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+const (
+	DefaultCacheSize = 1000
+
+	// sweepInterval is how often expired documents are deleted, at most
+	sweepInterval = time.Hour
+)
+
+type Config struct {
+	// CacheSize is the number of documents kept in memory
+	CacheSize int
+	// MaxStored caps the persisted_queries rows; documents registered beyond
+	// it are only cached in memory
+	MaxStored int
+	// TTL is how long a document is served after its registration, from
+	// memory or the table. Clients register an expired document again on
+	// PERSISTED_QUERY_NOT_FOUND.
+	TTL time.Duration
+}
+
+func NewConfig() *Config {
+	return &Config{
+		CacheSize: getEnvAsInt("PERSISTED_QUERIES_CACHE_SIZE", DefaultCacheSize),
+		MaxStored: getEnvAsInt("PERSISTED_QUERIES_MAX_STORED", 10000),
+		TTL:       getEnvAsDuration("PERSISTED_QUERIES_TTL", "720h"),
+	}
+}
+
+// Cache keeps APQ documents in an LRU, optionally backed by the
+// persisted_queries table so they survive restarts and are shared by replicas
+type Cache struct {
+	config *Config
+	memory *lru.LRU
+	store  *repository.PersistedQueryRepository
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+var _ graphql.Cache = &Cache{}
+
+// cached is a document in memory along with its registration time, which
+// expires it like the stored rows
+type cached struct {
+	query     string
+	createdAt time.Time
+}
+
+// NewCache creates the cache; store may be nil for a memory only cache
+func NewCache(config *Config, store *repository.PersistedQueryRepository) *Cache {
+	size := config.CacheSize
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	return &Cache{config: config, memory: lru.New(size), store: store}
+}
+
+func (c *Cache) Get(ctx context.Context, hash string) (interface{}, bool) {
+	if value, ok := c.memory.Get(ctx, hash); ok {
+		entry := value.(cached)
+		if c.expired(entry.createdAt) {
+			return nil, false
+		}
+		return entry.query, true
+	}
+	if c.store == nil {
+		return nil, false
+	}
+
+	stored, err := c.store.FindByHash(ctx, hash)
+	if err != nil {
+		logger.Log.WithField("hash", hash).Errorf("Failed to load persisted query: %v", err)
+		return nil, false
+	}
+	if stored == nil {
+		return nil, false
+	}
+
+	if c.expired(stored.CreatedAt) {
+		return nil, false
+	}
+
+	c.memory.Add(ctx, hash, cached{query: stored.Query, createdAt: stored.CreatedAt})
+	return stored.Query, true
+}
+
+// Add is called once the hash has been checked against the document
+func (c *Cache) Add(ctx context.Context, hash string, value interface{}) {
+	query, ok := value.(string)
+	if !ok {
+		return
+	}
+	c.memory.Add(ctx, hash, cached{query: query, createdAt: time.Now()})
+
+	if c.store == nil {
+		return
+	}
+	// Keep garbage out of the table, anyone can register a document
+	if _, err := parser.ParseQuery(&ast.Source{Input: query}); err != nil {
+		return
+	}
+	if c.config.MaxStored <= 0 {
+		return
+	}
+
+	c.sweep()
+	stored, err := c.store.Save(ctx, &entity.PersistedQuery{Hash: hash, Query: query}, c.config.MaxStored)
+	if err != nil {
+		logger.Log.WithField("hash", hash).Errorf("Failed to store persisted query: %v", err)
+		return
+	}
+	if !stored {
+		logger.Log.WithField("hash", hash).Debug("Persisted query kept in memory only, the table is full")
+	}
+}
+
+func (c *Cache) expired(createdAt time.Time) bool {
+	return c.config.TTL > 0 && time.Since(createdAt) > c.config.TTL
+}
+
+// sweep deletes the expired documents, at most once per interval per
+// instance
+func (c *Cache) sweep() {
+	if c.config.TTL <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	now := time.Now()
+	due := now.Sub(c.lastSweep) >= sweepInterval
+	if due {
+		c.lastSweep = now
+	}
+	c.mu.Unlock()
+	if !due {
+		return
+	}
+
+	go func() {
+		if _, err := c.store.DeleteCreatedBefore(context.Background(), now.Add(-c.config.TTL)); err != nil {
+			logger.Log.Warnf("Failed to sweep persisted queries: %v", err)
+		}
+	}()
+}
+
+func getEnvAsInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsDuration(key, defaultValue string) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		value = defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		duration, _ = time.ParseDuration(defaultValue)
+	}
+	return duration
+}
@@ -0,0 +1,173 @@
+// Package querylimit rejects GraphQL operations that are too deep, use too
+// many aliases or cost too much before any resolver runs.
+package querylimit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const extensionName = "QueryLimits"
+
+// Config holds the limits; zero disables a limit
+type Config struct {
+	MaxDepth      int
+	MaxAliases    int
+	MaxComplexity int
+}
+
+func NewConfig() *Config {
+	return &Config{
+		MaxDepth:      getEnvAsInt("QUERY_MAX_DEPTH", 10),
+		MaxAliases:    getEnvAsInt("QUERY_MAX_ALIASES", 15),
+		MaxComplexity: getEnvAsInt("QUERY_MAX_COMPLEXITY", 1000),
+	}
+}
+
+// Cost is computed for every operation and returned in the "cost" response
+// extension
+type Cost struct {
+	Complexity    int `json:"complexity"`
+	MaxComplexity int `json:"maxComplexity,omitempty"`
+	Depth         int `json:"depth"`
+	Aliases       int `json:"aliases"`
+}
+
+type Limits struct {
+	config *Config
+	schema graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+	graphql.ResponseInterceptor
+} = &Limits{}
+
+// New returns the extension. Field costs come from the Complexity functions
+// of the executable schema.
+func New(config *Config) *Limits {
+	return &Limits{config: config}
+}
+
+func (l *Limits) ExtensionName() string {
+	return extensionName
+}
+
+func (l *Limits) Validate(schema graphql.ExecutableSchema) error {
+	l.schema = schema
+	return nil
+}
+
+func (l *Limits) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	if rc.Operation == nil {
+		return nil
+	}
+
+	cost := &Cost{
+		Complexity:    complexity.Calculate(l.schema, rc.Operation, rc.Variables),
+		MaxComplexity: l.config.MaxComplexity,
+		Depth:         depth(rc.Operation.SelectionSet),
+		Aliases:       aliases(rc.Operation.SelectionSet),
+	}
+	rc.Stats.SetExtension(extensionName, cost)
+
+	switch {
+	case l.config.MaxDepth > 0 && cost.Depth > l.config.MaxDepth:
+		return limitError(exception.CodeQueryTooDeep,
+			fmt.Sprintf("Query depth %d exceeds the limit of %d", cost.Depth, l.config.MaxDepth))
+	case l.config.MaxAliases > 0 && cost.Aliases > l.config.MaxAliases:
+		return limitError(exception.CodeQueryTooManyAliases,
+			fmt.Sprintf("Query uses %d aliases, the limit is %d", cost.Aliases, l.config.MaxAliases))
+	case l.config.MaxComplexity > 0 && cost.Complexity > l.config.MaxComplexity:
+		return limitError(exception.CodeQueryTooComplex,
+			fmt.Sprintf("Query complexity %d exceeds the limit of %d", cost.Complexity, l.config.MaxComplexity))
+	}
+	return nil
+}
+
+func (l *Limits) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+	if resp == nil || !graphql.HasOperationContext(ctx) {
+		return resp
+	}
+
+	cost, ok := graphql.GetOperationContext(ctx).Stats.GetExtension(extensionName).(*Cost)
+	if !ok {
+		return resp
+	}
+	if resp.Extensions == nil {
+		resp.Extensions = make(map[string]interface{})
+	}
+	resp.Extensions["cost"] = cost
+	return resp
+}
+
+// depth returns the deepest field nesting of a selection set. Introspection
+// is bounded by the schema and not counted.
+func depth(selectionSet ast.SelectionSet) int {
+	deepest := 0
+	for _, selection := range selectionSet {
+		d := 0
+		switch s := selection.(type) {
+		case *ast.Field:
+			if s.Name == "__schema" || s.Name == "__type" {
+				continue
+			}
+			d = 1 + depth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				d = depth(s.Definition.SelectionSet)
+			}
+		case *ast.InlineFragment:
+			d = depth(s.SelectionSet)
+		}
+		if d > deepest {
+			deepest = d
+		}
+	}
+	return deepest
+}
+
+// aliases counts the aliased fields, fragments once per spread
+func aliases(selectionSet ast.SelectionSet) int {
+	count := 0
+	for _, selection := range selectionSet {
+		switch s := selection.(type) {
+		case *ast.Field:
+			if s.Alias != "" && s.Alias != s.Name {
+				count++
+			}
+			count += aliases(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				count += aliases(s.Definition.SelectionSet)
+			}
+		case *ast.InlineFragment:
+			count += aliases(s.SelectionSet)
+		}
+	}
+	return count
+}
+
+func limitError(code, details string) *gqlerror.Error {
+	err := exception.New(code).WithDetails(details)
+	return &gqlerror.Error{Message: err.Message, Err: err}
+}
+
+func getEnvAsInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
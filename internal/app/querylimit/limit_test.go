@@ -0,0 +1,216 @@
+package querylimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/executor"
+	"github.com/shennawardana23/graphql-pba/graph"
+	"github.com/shennawardana23/graphql-pba/graph/generated"
+	"github.com/shennawardana23/graphql-pba/internal/app/querylimit"
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+)
+
+var testConfig = &querylimit.Config{MaxDepth: 4, MaxAliases: 2, MaxComplexity: 100}
+
+// execute runs query against the generated schema with the limits installed.
+// Root fields resolve to null, so only the limits and pricing are exercised.
+func execute(t *testing.T, config *querylimit.Config, listMultiplier int, query string, variables map[string]interface{}) *graphql.Response {
+	t.Helper()
+
+	resolver := &graph.Resolver{}
+	exec := executor.New(generated.NewExecutableSchema(generated.Config{
+		Resolvers:  resolver,
+		Directives: graph.NewDirectiveRoot(resolver),
+		Complexity: graph.NewComplexityRoot(listMultiplier),
+	}))
+	exec.Use(querylimit.New(config))
+	exec.SetErrorPresenter(graph.ErrorPresenter)
+	exec.AroundRootFields(func(ctx context.Context, next graphql.RootResolver) graphql.Marshaler {
+		return graphql.Null
+	})
+
+	ctx := graphql.StartOperationTrace(context.Background())
+	params := &graphql.RawParams{
+		Query:     query,
+		Variables: variables,
+		ReadTime:  graphql.TraceTiming{Start: time.Now(), End: time.Now()},
+	}
+	rc, errs := exec.CreateOperationContext(ctx, params)
+	if errs != nil {
+		return exec.DispatchError(graphql.WithOperationContext(ctx, rc), errs)
+	}
+	responses, ctx := exec.DispatchOperation(ctx, rc)
+	return responses(ctx)
+}
+
+// cost returns the cost extension, failing the test when it is missing
+func cost(t *testing.T, resp *graphql.Response) *querylimit.Cost {
+	t.Helper()
+	c, ok := resp.Extensions["cost"].(*querylimit.Cost)
+	if !ok {
+		t.Fatalf("extensions.cost missing from %+v", resp.Extensions)
+	}
+	return c
+}
+
+// errorCode returns the code of the only error of resp, "" without errors
+func errorCode(t *testing.T, resp *graphql.Response) string {
+	t.Helper()
+	switch len(resp.Errors) {
+	case 0:
+		return ""
+	case 1:
+		code, _ := resp.Errors[0].Extensions["code"].(string)
+		return code
+	}
+	t.Fatalf("unexpected errors %v", resp.Errors)
+	return ""
+}
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		wantCode  string
+		want      querylimit.Cost
+	}{
+		{
+			name:  "depth at the limit",
+			query: `{ user(id: 1) { restaurants { user { id } } } }`,
+			want:  querylimit.Cost{Complexity: 22, Depth: 4},
+		},
+		{
+			name:     "depth over the limit",
+			query:    `{ user(id: 1) { restaurants { user { restaurants { id } } } } }`,
+			wantCode: exception.CodeQueryTooDeep,
+			want:     querylimit.Cost{Complexity: 122, Depth: 5},
+		},
+		{
+			name:     "depth through a fragment",
+			query:    `{ user(id: 1) { ...deep } } fragment deep on User { restaurants { user { restaurants { id } } } }`,
+			wantCode: exception.CodeQueryTooDeep,
+			want:     querylimit.Cost{Complexity: 122, Depth: 5},
+		},
+		{
+			name:  "introspection is not counted in depth",
+			query: `{ __schema { types { fields { type { ofType { name } } } } } }`,
+			want:  querylimit.Cost{},
+		},
+		{
+			name:  "aliases at the limit",
+			query: `{ a: user(id: 1) { id } b: user(id: 2) { id } }`,
+			want:  querylimit.Cost{Complexity: 4, Depth: 2, Aliases: 2},
+		},
+		{
+			name:     "aliases over the limit",
+			query:    `{ a: user(id: 1) { id } b: user(id: 2) { id } c: user(id: 3) { id } }`,
+			wantCode: exception.CodeQueryTooManyAliases,
+			want:     querylimit.Cost{Complexity: 6, Depth: 2, Aliases: 3},
+		},
+		{
+			name:     "aliases counted once per fragment spread",
+			query:    `{ user(id: 1) { ...names } restaurant(id: 1) { user { ...names } } } fragment names on User { a: name b: email }`,
+			wantCode: exception.CodeQueryTooManyAliases,
+			want:     querylimit.Cost{Complexity: 7, Depth: 3, Aliases: 4},
+		},
+		{
+			name:  "cost of a page under the limit",
+			query: `{ users(first: 5) { edges { node { id } } } }`,
+			want:  querylimit.Cost{Complexity: 16, Depth: 4},
+		},
+		{
+			name:     "cost of a page over the limit",
+			query:    `{ users(first: 50) { edges { node { id name } } } }`,
+			wantCode: exception.CodeQueryTooComplex,
+			want:     querylimit.Cost{Complexity: 201, Depth: 4},
+		},
+		{
+			name:      "page size from variables",
+			query:     `query ($n: Int) { users(last: $n) { edges { node { id name } } } }`,
+			variables: map[string]interface{}{"n": 50},
+			wantCode:  exception.CodeQueryTooComplex,
+			want:      querylimit.Cost{Complexity: 201, Depth: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := execute(t, testConfig, 10, tt.query, tt.variables)
+			if code := errorCode(t, resp); code != tt.wantCode {
+				t.Errorf("error code = %q, want %q", code, tt.wantCode)
+			}
+
+			tt.want.MaxComplexity = testConfig.MaxComplexity
+			if got := cost(t, resp); *got != tt.want {
+				t.Errorf("cost = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitsDisabled(t *testing.T) {
+	query := `{ a: user(id: 1) { restaurants { user { restaurants { b: id c: restaurantName } } } } }`
+	resp := execute(t, &querylimit.Config{}, 10, query, nil)
+	if code := errorCode(t, resp); code != "" {
+		t.Errorf("error code = %q, want none", code)
+	}
+	if got, want := *cost(t, resp), (querylimit.Cost{Complexity: 222, Depth: 5, Aliases: 3}); got != want {
+		t.Errorf("cost = %+v, want %+v", got, want)
+	}
+}
+
+func TestListCostMultiplier(t *testing.T) {
+	const (
+		field    = `{ user(id: 1) { restaurants { id } } }`
+		mutation = `mutation { restaurantsByUserID(userID: 1) { id restaurantName } }`
+	)
+
+	tests := []struct {
+		name       string
+		query      string
+		multiplier int
+		want       int
+	}{
+		{"default on a field", field, 0, 1 + (1 + 1*10)},
+		{"configured on a field", field, 3, 1 + (1 + 1*3)},
+		{"default on a mutation", mutation, 0, 1 + 2*10},
+		{"configured on a mutation", mutation, 3, 1 + 2*3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := execute(t, &querylimit.Config{}, tt.multiplier, tt.query, nil)
+			if got := cost(t, resp).Complexity; got != tt.want {
+				t.Errorf("complexity = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageSizePricing(t *testing.T) {
+	tests := []struct {
+		args string
+		want int
+	}{
+		{"", 1 + 20},
+		{"(first: 7)", 1 + 7},
+		{"(last: 3)", 1 + 3},
+		{"(first: 0)", 1},
+		// Out of range sizes are priced at the maximum page size
+		{"(first: 500)", 1 + 100},
+		{"(last: -1)", 1 + 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			resp := execute(t, &querylimit.Config{}, 10, `{ restaurants`+tt.args+` { totalCount } }`, nil)
+			if got := cost(t, resp).Complexity; got != tt.want {
+				t.Errorf("complexity = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+// Package ratelimit throttles GraphQL clients with token buckets. Every
+// operation spends a weight derived from its document.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+	"github.com/shennawardana23/graphql-pba/internal/util/logger"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+type Config struct {
+	// Burst is the bucket capacity; zero disables rate limiting
+	Burst float64
+	// Rate is the number of tokens refilled per second
+	Rate float64
+	// ListWeight is spent for every list selection, on top of the one token
+	// every field costs
+	ListWeight float64
+	// ClientHeader identifies anonymous clients behind the gateway; it is
+	// ignored on requests without the gateway secret, which are told apart
+	// by IP
+	ClientHeader string
+	// GatewaySecret is the AUTH_GATEWAY_SECRET a gateway sends along with
+	// ClientHeader
+	GatewaySecret string
+}
+
+func NewConfig() *Config {
+	return &Config{
+		Burst:         getEnvAsFloat("RATE_LIMIT_BURST", 1000),
+		Rate:          getEnvAsFloat("RATE_LIMIT_RATE", 50),
+		ListWeight:    getEnvAsFloat("RATE_LIMIT_LIST_WEIGHT", 10),
+		ClientHeader:  os.Getenv("RATE_LIMIT_CLIENT_HEADER"),
+		GatewaySecret: os.Getenv("AUTH_GATEWAY_SECRET"),
+	}
+}
+
+// Limit describes a token bucket
+type Limit struct {
+	Burst float64
+	Rate  float64
+}
+
+// Result is the bucket state after a take
+type Result struct {
+	Allowed bool
+	Tokens  float64
+}
+
+// Store keeps the buckets. Take refills the bucket of key for the time
+// elapsed since its last use, then removes weight tokens when enough are left.
+type Store interface {
+	Take(ctx context.Context, key string, weight float64, limit Limit) (Result, error)
+}
+
+// Limiter is a gqlgen extension charging every operation to the client stored
+// in the context by WithClient
+type Limiter struct {
+	config *Config
+	store  Store
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = &Limiter{}
+
+func New(config *Config, store Store) *Limiter {
+	return &Limiter{config: config, store: store}
+}
+
+func (l *Limiter) Enabled() bool {
+	return l.config.Burst > 0 && l.config.Rate > 0
+}
+
+func (l *Limiter) ExtensionName() string {
+	return "RateLimit"
+}
+
+func (l *Limiter) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (l *Limiter) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	c := clientFromContext(ctx)
+	if c == nil || rc.Operation == nil || !l.Enabled() {
+		return nil
+	}
+
+	limit := Limit{Burst: l.config.Burst, Rate: l.config.Rate}
+	weight := Weight(rc.Operation.SelectionSet, l.config.ListWeight)
+
+	result, err := l.store.Take(ctx, c.key, weight, limit)
+	if err != nil {
+		// Fail open, an unavailable store must not take the API down
+		logger.Log.WithField("client", c.key).Errorf("Rate limit store failed: %v", err)
+		return nil
+	}
+
+	header := c.header
+	header.Set("RateLimit-Limit", strconv.FormatFloat(limit.Burst, 'f', 0, 64))
+	header.Set("RateLimit-Remaining", strconv.FormatFloat(math.Floor(result.Tokens), 'f', 0, 64))
+	header.Set("RateLimit-Reset", strconv.Itoa(secondsUntil(limit.Burst-result.Tokens, limit.Rate)))
+	header.Set("RateLimit-Cost", strconv.FormatFloat(weight, 'f', 0, 64))
+
+	if result.Allowed {
+		return nil
+	}
+
+	// An operation heavier than the whole bucket will never pass
+	if weight > limit.Burst {
+		return limitError(exception.CodeQuotaLimitReached,
+			fmt.Sprintf("Operation costs %.0f, the budget is %.0f", weight, limit.Burst))
+	}
+
+	retryAfter := secondsUntil(weight-result.Tokens, limit.Rate)
+	header.Set("Retry-After", strconv.Itoa(retryAfter))
+	return limitError(exception.CodeRequestTooFast,
+		fmt.Sprintf("Operation costs %.0f, retry in %d seconds", weight, retryAfter))
+}
+
+// Weight costs every selected field 1 and every list selection listWeight more
+func Weight(selectionSet ast.SelectionSet, listWeight float64) float64 {
+	var weight float64
+	for _, selection := range selectionSet {
+		switch s := selection.(type) {
+		case *ast.Field:
+			weight++
+			if s.Definition != nil && s.Definition.Type != nil && s.Definition.Type.Elem != nil {
+				weight += listWeight
+			}
+			weight += Weight(s.SelectionSet, listWeight)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				weight += Weight(s.Definition.SelectionSet, listWeight)
+			}
+		case *ast.InlineFragment:
+			weight += Weight(s.SelectionSet, listWeight)
+		}
+	}
+	return weight
+}
+
+type clientKey struct{}
+
+type client struct {
+	key    string
+	header http.Header
+}
+
+// WithClient identifies the client charged for the operations of a request;
+// the RateLimit headers are written to header
+func WithClient(ctx context.Context, key string, header http.Header) context.Context {
+	return context.WithValue(ctx, clientKey{}, &client{key: key, header: header})
+}
+
+func clientFromContext(ctx context.Context) *client {
+	c, _ := ctx.Value(clientKey{}).(*client)
+	return c
+}
+
+func secondsUntil(tokens, rate float64) int {
+	if tokens <= 0 {
+		return 0
+	}
+	return int(math.Ceil(tokens / rate))
+}
+
+func limitError(code, details string) *gqlerror.Error {
+	err := exception.New(code).WithDetails(details)
+	return &gqlerror.Error{Message: err.Message, Err: err}
+}
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
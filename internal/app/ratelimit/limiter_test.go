@@ -0,0 +1,159 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/shennawardana23/graphql-pba/graph/generated"
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+var schema = generated.NewExecutableSchema(generated.Config{}).Schema()
+
+// parse validates query against the generated schema and returns its only
+// operation
+func parse(t *testing.T, query string) *ast.OperationDefinition {
+	t.Helper()
+	doc, err := gqlparser.LoadQuery(schema, query)
+	if err != nil {
+		t.Fatalf("LoadQuery(%q): %v", query, err)
+	}
+	return doc.Operations[0]
+}
+
+func TestWeight(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  float64
+	}{
+		{"scalar fields", `{ user(id: 1) { id name } }`, 3},
+		{"list field", `{ user(id: 1) { restaurants { id } } }`, 1 + (1 + 10) + 1},
+		{"connection", `{ users { edges { node { id } } totalCount } }`, 1 + (1 + 10) + 1 + 1 + 1},
+		{"aliases count every selection", `{ a: user(id: 1) { id } b: user(id: 2) { id } }`, 4},
+		{"fragment spread", `{ user(id: 1) { ...f } } fragment f on User { id name }`, 3},
+		{"inline fragment", `{ user(id: 1) { ... on User { restaurants { id } } } }`, 1 + (1 + 10) + 1},
+		{"mutation", `mutation { restaurantsByUserID(userID: 1) { id restaurantName } }`, (1 + 10) + 2},
+		{"typename", `{ __typename }`, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Weight(parse(t, tt.query).SelectionSet, 10); got != tt.want {
+				t.Errorf("Weight = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, weight float64, limit Limit) (Result, error) {
+	return Result{}, errors.New("store unavailable")
+}
+
+func TestLimiter(t *testing.T) {
+	store, c := newTestStore()
+	limiter := New(&Config{Burst: 20, Rate: 2, ListWeight: 10}, store)
+
+	light := parse(t, `{ user(id: 1) { restaurants { id } } }`)                             // weight 13
+	heavy := parse(t, `{ user(id: 1) { restaurants { id user { restaurants { id } } } } }`) // weight 26
+
+	steps := []struct {
+		name      string
+		operation *ast.OperationDefinition
+		wantCode  string
+		want      map[string]string
+	}{
+		{
+			"allowed",
+			light,
+			"",
+			map[string]string{"RateLimit-Limit": "20", "RateLimit-Remaining": "7", "RateLimit-Reset": "7", "RateLimit-Cost": "13", "Retry-After": ""},
+		},
+		{
+			"too fast",
+			light,
+			exception.CodeRequestTooFast,
+			map[string]string{"RateLimit-Limit": "20", "RateLimit-Remaining": "7", "RateLimit-Reset": "7", "RateLimit-Cost": "13", "Retry-After": "3"},
+		},
+		{
+			"heavier than the budget",
+			heavy,
+			exception.CodeQuotaLimitReached,
+			map[string]string{"RateLimit-Remaining": "7", "RateLimit-Cost": "26", "Retry-After": ""},
+		},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			header := http.Header{}
+			ctx := WithClient(context.Background(), "ip:192.0.2.1", header)
+			err := limiter.MutateOperationContext(ctx, &graphql.OperationContext{Operation: step.operation})
+
+			var customErr *exception.CustomError
+			code := ""
+			if err != nil && errors.As(err.Err, &customErr) {
+				code = customErr.Code
+			}
+			if err != nil && code == "" {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if code != step.wantCode {
+				t.Errorf("code = %q, want %q", code, step.wantCode)
+			}
+			for name, want := range step.want {
+				if got := header.Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+
+	// Refilling lets the light operation through again
+	c.Advance(3 * time.Second)
+	header := http.Header{}
+	ctx := WithClient(context.Background(), "ip:192.0.2.1", header)
+	if err := limiter.MutateOperationContext(ctx, &graphql.OperationContext{Operation: light}); err != nil {
+		t.Errorf("after refilling: %v", err)
+	}
+	if got := header.Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("RateLimit-Remaining after refilling = %q, want 0", got)
+	}
+}
+
+func TestLimiterSkips(t *testing.T) {
+	operation := parse(t, `{ user(id: 1) { id } }`)
+
+	tests := []struct {
+		name    string
+		limiter *Limiter
+		client  bool
+	}{
+		{"disabled", New(&Config{Burst: 0, Rate: 2}, NewMemoryStore()), true},
+		{"without a client", New(&Config{Burst: 20, Rate: 2}, NewMemoryStore()), false},
+		{"failing store", New(&Config{Burst: 20, Rate: 2}, failingStore{}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			ctx := context.Background()
+			if tt.client {
+				ctx = WithClient(ctx, "ip:192.0.2.1", header)
+			}
+			if err := tt.limiter.MutateOperationContext(ctx, &graphql.OperationContext{Operation: operation}); err != nil {
+				t.Errorf("MutateOperationContext = %v, want nil", err)
+			}
+			if len(header) != 0 {
+				t.Errorf("headers = %v, want none", header)
+			}
+		})
+	}
+}
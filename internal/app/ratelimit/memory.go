@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// MemoryStore keeps the buckets of a single instance
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), lastSweep: time.Now(), now: time.Now}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, weight float64, limit Limit) (Result, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now, limit)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: limit.Burst, updated: now}
+		s.buckets[key] = b
+	}
+
+	b.tokens = math.Min(limit.Burst, b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+	b.updated = now
+
+	if b.tokens < weight {
+		return Result{Allowed: false, Tokens: b.tokens}, nil
+	}
+	b.tokens -= weight
+	return Result{Allowed: true, Tokens: b.tokens}, nil
+}
+
+// sweep drops the buckets that have refilled completely, they are
+// indistinguishable from new ones
+func (s *MemoryStore) sweep(now time.Time, limit Limit) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*limit.Rate >= limit.Burst {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// clock is a manually advanced time source
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestStore() (*MemoryStore, *clock) {
+	c := &clock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewMemoryStore()
+	s.now = c.Now
+	s.lastSweep = c.now
+	return s, c
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	s, c := newTestStore()
+	limit := Limit{Burst: 10, Rate: 2}
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		key     string
+		weight  float64
+		allowed bool
+		tokens  float64
+	}{
+		{"new bucket starts full", 0, "a", 4, true, 6},
+		{"denied without enough tokens", 0, "a", 7, false, 6},
+		{"denied take spends nothing", 0, "a", 6, true, 0},
+		{"refills at the rate", 1500 * time.Millisecond, "a", 3, true, 0},
+		{"refill is capped at the burst", time.Hour, "a", 0, true, 10},
+		{"keys have their own bucket", 0, "b", 10, true, 0},
+		{"other keys are untouched", 0, "a", 10, true, 0},
+		{"heavier than the burst never passes", time.Hour, "a", 11, false, 10},
+	}
+
+	for _, step := range steps {
+		c.Advance(step.advance)
+		result, err := s.Take(context.Background(), step.key, step.weight, limit)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if result.Allowed != step.allowed || result.Tokens != step.tokens {
+			t.Errorf("%s: Take = %+v, want allowed %v with %v tokens", step.name, result, step.allowed, step.tokens)
+		}
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s, c := newTestStore()
+	limit := Limit{Burst: 100, Rate: 1}
+
+	ctx := context.Background()
+	s.Take(ctx, "idle", 10, limit)
+	c.Advance(50 * time.Second)
+	s.Take(ctx, "busy", 90, limit)
+
+	// Before the interval nothing is swept; after it, the idle bucket has
+	// refilled and is dropped while the busy one still needs 31 seconds
+	c.Advance(sweepInterval - 51*time.Second)
+	s.Take(ctx, "other", 1, limit)
+	if len(s.buckets) != 3 {
+		t.Fatalf("buckets before the sweep interval = %d, want 3", len(s.buckets))
+	}
+
+	c.Advance(50 * time.Second)
+	s.Take(ctx, "other", 1, limit)
+	if _, ok := s.buckets["idle"]; ok {
+		t.Error("refilled bucket was not swept")
+	}
+	if _, ok := s.buckets["busy"]; !ok {
+		t.Error("partially spent bucket was swept")
+	}
+}
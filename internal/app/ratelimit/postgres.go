@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/shennawardana23/graphql-pba/internal/util/logger"
+)
+
+// refilled is the token count of an existing bucket after refilling it for
+// the time since its last use; ?1 is the burst and ?3 the rate
+const refilled = `LEAST(?1, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at) * ?3)`
+
+// takeQuery creates or refills the bucket and takes ?2 tokens in a single
+// statement, so concurrent replicas cannot both spend the same tokens
+const takeQuery = `
+INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+VALUES (?0, CASE WHEN ?2 <= ?1 THEN ?1 - ?2 ELSE ?1 END, ?2 <= ?1, now())
+ON CONFLICT (key) DO UPDATE SET
+	tokens = CASE WHEN ` + refilled + ` >= ?2 THEN ` + refilled + ` - ?2 ELSE ` + refilled + ` END,
+	allowed = ` + refilled + ` >= ?2,
+	updated_at = now()
+RETURNING tokens, allowed`
+
+// PostgresStore keeps the buckets in the rate_limit_buckets table so the
+// limits hold across replicas
+type PostgresStore struct {
+	db *pg.DB
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewPostgresStore(db *pg.DB) *PostgresStore {
+	return &PostgresStore{db: db, lastSweep: time.Now()}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, weight float64, limit Limit) (Result, error) {
+	var result Result
+	_, err := s.db.QueryOneContext(ctx, pg.Scan(&result.Tokens, &result.Allowed), takeQuery,
+		key, limit.Burst, weight, limit.Rate)
+	if err != nil {
+		return Result{}, err
+	}
+
+	s.sweep(limit)
+	return result, nil
+}
+
+// sweep deletes the buckets that have refilled completely, at most once per
+// interval per instance
+func (s *PostgresStore) sweep(limit Limit) {
+	s.mu.Lock()
+	now := time.Now()
+	due := now.Sub(s.lastSweep) >= sweepInterval
+	if due {
+		s.lastSweep = now
+	}
+	s.mu.Unlock()
+	if !due {
+		return
+	}
+
+	idleSeconds := limit.Burst / limit.Rate
+	go func() {
+		_, err := s.db.Exec("DELETE FROM rate_limit_buckets WHERE updated_at < now() - ? * interval '1 second'", idleSeconds)
+		if err != nil {
+			logger.Log.Warnf("Failed to sweep rate limit buckets: %v", err)
+		}
+	}()
+}
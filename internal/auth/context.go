@@ -0,0 +1,61 @@
+// Package auth issues and verifies access and refresh tokens and carries the
+// authenticated principal through the request context.
+package auth
+
+import (
+	"context"
+
+	"github.com/shennawardana23/graphql-pba/internal/util/i18n"
+	"github.com/shennawardana23/graphql-pba/internal/util/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	principalKey  struct{}
+	tokenErrorKey struct{}
+)
+
+// Log entries of authenticated requests carry the user id
+func init() {
+	logger.RegisterContextFields(func(ctx context.Context) logrus.Fields {
+		if principal := PrincipalFromContext(ctx); principal != nil {
+			return logrus.Fields{"user_id": principal.UserID}
+		}
+		return nil
+	})
+}
+
+// Messages follow the locale preference of the authenticated user
+func init() {
+	i18n.RegisterPreference(func(ctx context.Context) string {
+		if principal := PrincipalFromContext(ctx); principal != nil {
+			return principal.Locale
+		}
+		return ""
+	})
+}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal, or nil for an
+// anonymous request
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
+
+// WithTokenError returns a copy of ctx recording why the credentials of the
+// request were rejected, the request going on as anonymous
+func WithTokenError(ctx context.Context, err error) context.Context {
+	return context.WithValue(ctx, tokenErrorKey{}, err)
+}
+
+// TokenErrorFromContext returns the error recorded by WithTokenError, nil
+// when the request sent no credentials or valid ones
+func TokenErrorFromContext(ctx context.Context) error {
+	err, _ := ctx.Value(tokenErrorKey{}).(error)
+	return err
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+)
+
+// IdentityResolver works out who sent a request. It returns a nil principal
+// when the request carries no identity it understands.
+type IdentityResolver interface {
+	Resolve(r *http.Request) (*Principal, error)
+}
+
+// BearerTokenResolver trusts access tokens issued by TokenService
+type BearerTokenResolver struct {
+	Tokens *TokenService
+}
+
+func (b *BearerTokenResolver) Resolve(r *http.Request) (*Principal, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return nil, nil
+	}
+
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return nil, exception.ErrTokenInvalid
+	}
+
+	claims, err := b.Tokens.VerifyAccessToken(strings.TrimSpace(token))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Principal{
+		UserID: claims.UserID(),
+		Email:  claims.Email,
+		Roles:  claims.Roles,
+		Locale: claims.Locale,
+	}, nil
+}
+
+// GatewayHeaderResolver trusts identity headers set by an API gateway that
+// already authenticated the caller. The headers are only honoured when the
+// request also carries the shared gateway secret.
+type GatewayHeaderResolver struct {
+	Secret string
+}
+
+const (
+	GatewaySecretHeader = "X-Gateway-Secret"
+	GatewayUserIDHeader = "X-User-Id"
+	GatewayEmailHeader  = "X-User-Email"
+	GatewayRolesHeader  = "X-User-Roles"
+	GatewayLocaleHeader = "X-User-Locale"
+)
+
+// TrustedGateway reports whether r carries the shared gateway secret, always
+// false when no secret is configured
+func TrustedGateway(r *http.Request, secret string) bool {
+	sent := r.Header.Get(GatewaySecretHeader)
+	return secret != "" && sent != "" && subtle.ConstantTimeCompare([]byte(sent), []byte(secret)) == 1
+}
+
+func (g *GatewayHeaderResolver) Resolve(r *http.Request) (*Principal, error) {
+	secret := r.Header.Get(GatewaySecretHeader)
+	if secret == "" {
+		return nil, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(g.Secret)) != 1 {
+		return nil, exception.ErrTokenInvalid
+	}
+
+	userID, err := strconv.ParseInt(r.Header.Get(GatewayUserIDHeader), 10, 64)
+	if err != nil || userID <= 0 {
+		return nil, exception.ErrTokenInvalid
+	}
+
+	var roles []string
+	for _, role := range strings.Split(r.Header.Get(GatewayRolesHeader), ",") {
+		if role = strings.ToUpper(strings.TrimSpace(role)); role != "" {
+			roles = append(roles, role)
+		}
+	}
+
+	return &Principal{
+		UserID: userID,
+		Email:  r.Header.Get(GatewayEmailHeader),
+		Roles:  roles,
+		Locale: r.Header.Get(GatewayLocaleHeader),
+	}, nil
+}
+
+// ChainResolver asks each resolver in turn and uses the first identity found
+type ChainResolver []IdentityResolver
+
+func (c ChainResolver) Resolve(r *http.Request) (*Principal, error) {
+	for _, resolver := range c {
+		principal, err := resolver.Resolve(r)
+		if err != nil || principal != nil {
+			return principal, err
+		}
+	}
+	return nil, nil
+}
+
+// NewIdentityResolver builds the resolver chain from the environment. The
+// gateway resolver is only enabled when AUTH_GATEWAY_SECRET is set.
+func NewIdentityResolver(tokens *TokenService) IdentityResolver {
+	chain := ChainResolver{}
+	if secret := os.Getenv("AUTH_GATEWAY_SECRET"); secret != "" {
+		chain = append(chain, &GatewayHeaderResolver{Secret: secret})
+	}
+	return append(chain, &BearerTokenResolver{Tokens: tokens})
+}
@@ -0,0 +1,34 @@
+package auth
+
+import "github.com/shennawardana23/graphql-pba/internal/entity"
+
+// Principal is the caller a request acts on behalf of
+type Principal struct {
+	UserID int64
+	Email  string
+	Roles  []string
+
+	// Locale is the preferred language of messages, "" when not set
+	Locale string
+}
+
+// HasRole reports whether the principal holds role. Admins hold every role.
+func (p *Principal) HasRole(role string) bool {
+	if p == nil {
+		return false
+	}
+	for _, r := range p.Roles {
+		if r == role || r == entity.RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// CanActFor reports whether the principal may act on data owned by userID
+func (p *Principal) CanActFor(userID int64) bool {
+	if p == nil {
+		return false
+	}
+	return p.UserID == userID || p.HasRole(entity.RoleAdmin)
+}
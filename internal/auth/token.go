@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+)
+
+const tokenIssuer = "graphql-pba"
+
+type Config struct {
+	Secret     []byte
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+func NewConfig() *Config {
+	return &Config{
+		Secret:     []byte(os.Getenv("JWT_SECRET")),
+		AccessTTL:  getEnvAsDuration("JWT_ACCESS_TTL", 15*time.Minute),
+		RefreshTTL: getEnvAsDuration("JWT_REFRESH_TTL", 30*24*time.Hour),
+	}
+}
+
+// Claims are the access token claims; the subject is the user id
+type Claims struct {
+	Email  string   `json:"email"`
+	Roles  []string `json:"roles"`
+	Locale string   `json:"locale,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// UserID returns the numeric user id carried in the subject claim
+func (c *Claims) UserID() int64 {
+	id, _ := strconv.ParseInt(c.Subject, 10, 64)
+	return id
+}
+
+type TokenService struct {
+	config *Config
+}
+
+func NewTokenService(config *Config) (*TokenService, error) {
+	if len(config.Secret) < 32 {
+		return nil, fmt.Errorf("JWT_SECRET must be at least 32 bytes")
+	}
+	return &TokenService{config: config}, nil
+}
+
+// IssueAccessToken signs a short-lived HS256 access token for the user. The
+// locale preference travels in the token so requests need no lookup.
+func (s *TokenService) IssueAccessToken(userID int64, email string, roles []string, locale string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.config.AccessTTL)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		Email:  email,
+		Roles:  roles,
+		Locale: locale,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   strconv.FormatInt(userID, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+
+	signed, err := token.SignedString(s.config.Secret)
+	return signed, expiresAt, err
+}
+
+// VerifyAccessToken parses and validates an access token. It returns
+// exception.ErrTokenExpired or exception.ErrTokenInvalid on failure.
+func (s *TokenService) VerifyAccessToken(tokenString string) (*Claims, error) {
+	claims := new(Claims)
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return s.config.Secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return nil, exception.ErrTokenExpired
+	case err != nil:
+		return nil, exception.ErrTokenInvalid
+	case claims.UserID() <= 0:
+		return nil, exception.ErrTokenInvalid
+	}
+	return claims, nil
+}
+
+// NewRefreshToken returns an opaque random refresh token, the hash to store
+// for it and its expiry
+func (s *TokenService) NewRefreshToken() (token, hash string, expiresAt time.Time, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", time.Time{}, err
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashRefreshToken(token), time.Now().Add(s.config.RefreshTTL), nil
+}
+
+// HashRefreshToken is the lookup key stored instead of the raw refresh token
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
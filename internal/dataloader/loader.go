@@ -0,0 +1,112 @@
+// Package dataloader batches and caches keyed lookups made while resolving a
+// single request so N sibling field resolvers share one query.
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultWait     = 2 * time.Millisecond
+	DefaultMaxBatch = 1000
+)
+
+// FetchFunc loads the values of keys in one round trip. Keys missing from the
+// returned map resolve to the zero value.
+type FetchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// Loader collects keys requested within the wait window and resolves them
+// with a single FetchFunc call. It caches results for its own lifetime, so it
+// must be created per request.
+type Loader[K comparable, V any] struct {
+	fetch    FetchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[K]*result[V]
+	pending []K
+	timer   *time.Timer
+}
+
+func New[K comparable, V any](fetch FetchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     DefaultWait,
+		maxBatch: DefaultMaxBatch,
+		cache:    make(map[K]*result[V]),
+	}
+}
+
+// Load returns the value for key, waiting for the batch it joins to be fetched
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	res, ok := l.cache[key]
+	if !ok {
+		res = &result[V]{done: make(chan struct{})}
+		l.cache[key] = res
+		l.pending = append(l.pending, key)
+
+		// The batch outlives the resolver that started it, so it must not be
+		// cancelled when that one field finishes.
+		batchCtx := context.WithoutCancel(ctx)
+		switch {
+		case len(l.pending) >= l.maxBatch:
+			if l.timer != nil {
+				l.timer.Stop()
+			}
+			go l.dispatch(batchCtx)
+		case len(l.pending) == 1:
+			l.timer = time.AfterFunc(l.wait, func() { l.dispatch(batchCtx) })
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-res.done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+func (l *Loader[K, V]) dispatch(ctx context.Context) {
+	l.mu.Lock()
+	keys := l.pending
+	l.pending = nil
+	results := make([]*result[V], len(keys))
+	for i, key := range keys {
+		results[i] = l.cache[key]
+	}
+	l.mu.Unlock()
+
+	if len(keys) == 0 {
+		return
+	}
+
+	values, err := l.fetch(ctx, keys)
+
+	if err != nil {
+		// Do not cache failures so a later field can retry
+		l.mu.Lock()
+		for _, key := range keys {
+			delete(l.cache, key)
+		}
+		l.mu.Unlock()
+	}
+
+	for i, key := range keys {
+		results[i].value = values[key]
+		results[i].err = err
+		close(results[i].done)
+	}
+}
@@ -0,0 +1,9 @@
+package entity
+
+import "time"
+
+type PersistedQuery struct {
+	Hash      string    `pg:"hash,pk"`
+	Query     string    `pg:"query,notnull"`
+	CreatedAt time.Time `pg:"created_at,notnull"`
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+type RefreshToken struct {
+	ID        int64      `pg:"id,pk"`
+	UserID    int64      `pg:"user_id,notnull"`
+	TokenHash string     `pg:"token_hash,notnull"`
+	ExpiresAt time.Time  `pg:"expires_at,notnull"`
+	RevokedAt *time.Time `pg:"revoked_at"`
+	CreatedAt time.Time  `pg:"created_at,notnull"`
+}
@@ -2,12 +2,20 @@ package entity
 
 import "time"
 
+const (
+	RoleUser  = "USER"
+	RoleAdmin = "ADMIN"
+)
+
 type User struct {
-	ID        int64     `pg:"id,pk"`
-	Name      string    `pg:"name,notnull"`
-	Email     string    `pg:"email,notnull"`
-	CreatedAt time.Time `pg:"created_at"`
-	UpdatedAt time.Time `pg:"updated_at"`
+	ID           int64     `pg:"id,pk"`
+	Name         string    `pg:"name,notnull"`
+	Email        string    `pg:"email,notnull"`
+	PasswordHash string    `pg:"password_hash" json:"-"`
+	Role         string    `pg:"role,notnull,default:'USER'"`
+	Locale       string    `pg:"locale"` // Preferred language of messages, e.g. "id"
+	CreatedAt    time.Time `pg:"created_at"`
+	UpdatedAt    time.Time `pg:"updated_at"`
 }
 
 type CreateUserInput struct {
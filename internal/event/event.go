@@ -0,0 +1,169 @@
+// Package event defines the domain change events published by the mutations.
+package event
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shennawardana23/graphql-pba/internal/entity"
+	"github.com/shennawardana23/graphql-pba/internal/pubsub"
+	"github.com/shennawardana23/graphql-pba/internal/util/logger"
+)
+
+type Action string
+
+// Broker channels carrying the change events
+const (
+	ChannelRestaurants = "restaurants_changed"
+	ChannelUsers       = "users_changed"
+)
+
+const (
+	// publishTimeout bounds the delivery of an event to the broker
+	publishTimeout = 5 * time.Second
+	// loadTimeout bounds the query loading the row of a reference event
+	loadTimeout = 5 * time.Second
+)
+
+const (
+	ActionCreated Action = "CREATED"
+	ActionUpdated Action = "UPDATED"
+	ActionDeleted Action = "DELETED"
+)
+
+// RestaurantChanged carries the restaurant after the change, or as it was
+// before deletion
+type RestaurantChanged struct {
+	Action     Action            `json:"action"`
+	Restaurant entity.Restaurant `json:"restaurant"`
+	// Reference marks an event too large for the broker, sent with the id and
+	// owner only. The receiving instance loads the rest of the row.
+	Reference bool `json:"reference,omitempty"`
+}
+
+// UserChanged carries the user after the change, or as it was before deletion
+type UserChanged struct {
+	Action Action      `json:"action"`
+	User   entity.User `json:"user"`
+	// Reference marks an event sent with the user id only
+	Reference bool `json:"reference,omitempty"`
+}
+
+// RestaurantFinder loads the restaurants of reference events
+type RestaurantFinder interface {
+	FindByID(ctx context.Context, id int64) (*entity.Restaurant, error)
+}
+
+// UserFinder loads the users of reference events
+type UserFinder interface {
+	FindByID(ctx context.Context, id int64) (*entity.User, error)
+}
+
+// Bus publishes changes through the broker and feeds the events the broker
+// delivers back, including those of other instances, into one topic per
+// event type
+type Bus struct {
+	broker      pubsub.Broker
+	restaurants RestaurantFinder
+	users       UserFinder
+
+	Restaurants *pubsub.Topic[RestaurantChanged]
+	Users       *pubsub.Topic[UserChanged]
+}
+
+// NewBus creates the topics with bufferSize events buffered per subscriber.
+// The finders load the rows of events received as a reference.
+func NewBus(broker pubsub.Broker, bufferSize int, restaurants RestaurantFinder, users UserFinder) *Bus {
+	b := &Bus{
+		broker:      broker,
+		restaurants: restaurants,
+		users:       users,
+		Restaurants: pubsub.NewTopic[RestaurantChanged]("restaurants", bufferSize),
+		Users:       pubsub.NewTopic[UserChanged]("users", bufferSize),
+	}
+
+	pubsub.Register(broker, ChannelRestaurants, b.receiveRestaurant)
+	pubsub.Register(broker, ChannelUsers, b.receiveUser)
+	return b
+}
+
+// PublishRestaurant announces a committed restaurant change. Failures are
+// logged, the change itself already happened.
+func (b *Bus) PublishRestaurant(ctx context.Context, action Action, restaurant entity.Restaurant) {
+	// Relations are not part of the event
+	restaurant.User = entity.User{}
+	b.publish(ctx, ChannelRestaurants,
+		RestaurantChanged{Action: action, Restaurant: restaurant},
+		RestaurantChanged{Action: action, Restaurant: entity.Restaurant{ID: restaurant.ID, UserID: restaurant.UserID}, Reference: true})
+}
+
+// PublishUser announces a committed user change
+func (b *Bus) PublishUser(ctx context.Context, action Action, user entity.User) {
+	b.publish(ctx, ChannelUsers,
+		UserChanged{Action: action, User: user},
+		UserChanged{Action: action, User: entity.User{ID: user.ID}, Reference: true})
+}
+
+// publish sends event, or reference when the broker cannot carry event
+func (b *Bus) publish(ctx context.Context, channel string, event, reference interface{}) {
+	// The change is committed, so a client disconnecting must not keep the
+	// other instances from hearing about it
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), publishTimeout)
+	defer cancel()
+
+	err := pubsub.Publish(ctx, b.broker, channel, event)
+	if errors.Is(err, pubsub.ErrPayloadTooLarge) {
+		err = pubsub.Publish(ctx, b.broker, channel, reference)
+	}
+	if err != nil {
+		logger.Log.WithField("channel", channel).Errorf("Failed to publish event: %v", err)
+	}
+}
+
+// receiveRestaurant feeds a delivered event into the topic, loading the row
+// of a reference first. Deleted rows cannot be loaded, their reference is
+// delivered as is.
+func (b *Bus) receiveRestaurant(e RestaurantChanged) {
+	if !e.Reference || e.Action == ActionDeleted {
+		b.Restaurants.Publish(e)
+		return
+	}
+
+	// Handlers must not block the delivery goroutine
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), loadTimeout)
+		defer cancel()
+
+		restaurant, err := b.restaurants.FindByID(ctx, e.Restaurant.ID)
+		if err != nil {
+			logger.Log.WithField("restaurant_id", e.Restaurant.ID).Warnf("Failed to load referenced restaurant: %v", err)
+		} else if restaurant != nil {
+			restaurant.User = entity.User{}
+			e.Restaurant, e.Reference = *restaurant, false
+		}
+		b.Restaurants.Publish(e)
+	}()
+}
+
+// receiveUser feeds a delivered user event into the topic, like
+// receiveRestaurant
+func (b *Bus) receiveUser(e UserChanged) {
+	if !e.Reference || e.Action == ActionDeleted {
+		b.Users.Publish(e)
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), loadTimeout)
+		defer cancel()
+
+		user, err := b.users.FindByID(ctx, e.User.ID)
+		if err != nil {
+			logger.Log.WithField("user_id", e.User.ID).Warnf("Failed to load referenced user: %v", err)
+		} else if user != nil {
+			e.User, e.Reference = *user, false
+		}
+		b.Users.Publish(e)
+	}()
+}
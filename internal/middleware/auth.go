@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gin-gonic/gin"
+	"github.com/shennawardana23/graphql-pba/internal/auth"
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+)
+
+// Auth resolves the caller identity and stores the principal in the request
+// context. Requests without an identity pass through as anonymous; the schema
+// directives decide what requires authentication. So do requests with an
+// expired or invalid token, so that login and refreshToken still work: the
+// token error is recorded for the directives to report.
+func Auth(identity auth.IdentityResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := identity.Resolve(c.Request)
+		if err != nil {
+			c.Request = c.Request.WithContext(auth.WithTokenError(c.Request.Context(), err))
+			c.Next()
+			return
+		}
+
+		if principal != nil {
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		}
+		c.Next()
+	}
+}
+
+// WebsocketInit authenticates a subscription from the Authorization value of
+// the connection_init payload, since browsers cannot set headers on the
+// WebSocket handshake. A principal resolved from the handshake is kept when the
+// payload carries none.
+func WebsocketInit(identity auth.IdentityResolver) transport.WebsocketInitFunc {
+	return func(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+		req := (&http.Request{Header: http.Header{}}).WithContext(ctx)
+		if authorization := payload.Authorization(); authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+
+		principal, err := identity.Resolve(req)
+		if err != nil {
+			return ctx, nil, exception.TranslateTokenError(err)
+		}
+		if principal != nil {
+			ctx = auth.WithPrincipal(ctx, principal)
+		}
+		return ctx, nil, nil
+	}
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
 	"github.com/shennawardana23/graphql-pba/internal/util/exception"
 )
@@ -10,9 +12,9 @@ func ErrorHandler() gin.HandlerFunc {
 		c.Next()
 
 		if len(c.Errors) > 0 {
-			err := c.Errors[0].Err
-			if validationErr, ok := err.(*exception.CustomError); ok {
-				c.JSON(400, gin.H{
+			var validationErr *exception.CustomError
+			if errors.As(c.Errors[0].Err, &validationErr) {
+				c.JSON(exception.HTTPStatus(validationErr.Code), gin.H{
 					"errors": []gin.H{
 						{
 							"message": validationErr.Message,
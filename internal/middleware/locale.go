@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shennawardana23/graphql-pba/internal/util/i18n"
+)
+
+// Locale negotiates the language of error messages from Accept-Language. The
+// preference of an authenticated user takes precedence, see i18n.Locale.
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if header := c.GetHeader("Accept-Language"); header != "" {
+			c.Request = c.Request.WithContext(i18n.WithLocale(c.Request.Context(), i18n.Negotiate(header)))
+		}
+		c.Next()
+	}
+}
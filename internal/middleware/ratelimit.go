@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shennawardana23/graphql-pba/internal/app/ratelimit"
+	"github.com/shennawardana23/graphql-pba/internal/auth"
+)
+
+// maxClientIDLength bounds the bucket keys a gateway can make up
+const maxClientIDLength = 128
+
+// RateLimit identifies the client charged by the rate limiter: authenticated
+// callers by user id, anonymous ones by the ClientHeader value a trusted
+// gateway sets, by IP otherwise. Run it after Auth.
+func RateLimit(config *ratelimit.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if principal := auth.PrincipalFromContext(c.Request.Context()); principal != nil {
+			key = "user:" + strconv.FormatInt(principal.UserID, 10)
+		} else if config.ClientHeader != "" && auth.TrustedGateway(c.Request, config.GatewaySecret) {
+			if id := c.GetHeader(config.ClientHeader); id != "" {
+				if len(id) > maxClientIDLength {
+					id = id[:maxClientIDLength]
+				}
+				key = "client:" + id
+			}
+		}
+
+		c.Request = c.Request.WithContext(ratelimit.WithClient(c.Request.Context(), key, c.Writer.Header()))
+		c.Next()
+	}
+}
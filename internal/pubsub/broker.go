@@ -0,0 +1,73 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/shennawardana23/graphql-pba/internal/util/logger"
+)
+
+// ErrPayloadTooLarge is returned by brokers whose transport cannot carry the
+// payload. Publishers may retry with a smaller one.
+var ErrPayloadTooLarge = errors.New("payload too large")
+
+// Broker carries JSON payloads between publishers and the consumers
+// registered on a channel, possibly across instances
+type Broker interface {
+	Publish(ctx context.Context, channel string, payload []byte) error
+	// Subscribe registers handler for every payload received on channel.
+	// Handlers run on the delivery goroutine and must not block.
+	Subscribe(channel string, handler func(payload []byte))
+}
+
+// Publish encodes event as JSON and publishes it on channel
+func Publish[T any](ctx context.Context, b Broker, channel string, event T) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return b.Publish(ctx, channel, payload)
+}
+
+// Register subscribes a typed consumer to channel. Payloads that do not
+// decode into T are logged and skipped.
+func Register[T any](b Broker, channel string, consume func(T)) {
+	b.Subscribe(channel, func(payload []byte) {
+		var event T
+		if err := json.Unmarshal(payload, &event); err != nil {
+			logger.Log.WithField("channel", channel).Warnf("Dropping undecodable event: %v", err)
+			return
+		}
+		consume(event)
+	})
+}
+
+// MemoryBroker delivers payloads synchronously within the process, for single
+// instance deployments
+type MemoryBroker struct {
+	mu       sync.RWMutex
+	handlers map[string][]func([]byte)
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{handlers: make(map[string][]func([]byte))}
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, channel string, payload []byte) error {
+	b.mu.RLock()
+	handlers := b.handlers[channel]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(payload)
+	}
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(channel string, handler func([]byte)) {
+	b.mu.Lock()
+	b.handlers[channel] = append(b.handlers[channel], handler)
+	b.mu.Unlock()
+}
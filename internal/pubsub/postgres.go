@@ -0,0 +1,135 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/shennawardana23/graphql-pba/internal/util/logger"
+)
+
+const (
+	// notificationBuffer is the number of notifications queued between the
+	// listener connection and the handlers
+	notificationBuffer = 256
+
+	// maxNotifyPayload is the size from which Postgres rejects NOTIFY payloads
+	maxNotifyPayload = 8000
+
+	reconnectDelay    = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+// PostgresBroker fans payloads out to every instance through LISTEN/NOTIFY.
+// All consumers of an instance share one listener connection. Notifications
+// sent while that connection is down are lost.
+type PostgresBroker struct {
+	db *pg.DB
+
+	mu       sync.RWMutex
+	handlers map[string][]func([]byte)
+	listener *pg.Listener
+}
+
+func NewPostgresBroker(db *pg.DB) *PostgresBroker {
+	return &PostgresBroker{
+		db:       db,
+		handlers: make(map[string][]func([]byte)),
+	}
+}
+
+// Publish sends payload with NOTIFY on its own connection, outside any
+// transaction, so listeners receive it right away. Payloads of 8000 bytes or
+// more, which Postgres rejects, fail with ErrPayloadTooLarge.
+func (b *PostgresBroker) Publish(ctx context.Context, channel string, payload []byte) error {
+	if len(payload) >= maxNotifyPayload {
+		return ErrPayloadTooLarge
+	}
+	_, err := b.db.ExecContext(ctx, "SELECT pg_notify(?, ?)", channel, string(payload))
+	return err
+}
+
+func (b *PostgresBroker) Subscribe(channel string, handler func([]byte)) {
+	b.mu.Lock()
+	first := len(b.handlers[channel]) == 0
+	b.handlers[channel] = append(b.handlers[channel], handler)
+	listener := b.listener
+	b.mu.Unlock()
+
+	// Channels registered after Run started are added to the live listener
+	if first && listener != nil {
+		if err := listener.Listen(context.Background(), channel); err != nil {
+			logger.Log.WithField("channel", channel).Errorf("Failed to listen: %v", err)
+		}
+	}
+}
+
+// Run listens on the subscribed channels and dispatches notifications until
+// ctx is done. go-pg reconnects a broken listener connection by itself; when
+// the listener is closed anyway, a new one is opened with backoff.
+func (b *PostgresBroker) Run(ctx context.Context) {
+	delay := reconnectDelay
+	for {
+		started := time.Now()
+		b.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if time.Since(started) > maxReconnectDelay {
+			delay = reconnectDelay
+		}
+		logger.Log.Warnf("Event listener closed, reconnecting in %s", delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+func (b *PostgresBroker) listen(ctx context.Context) {
+	b.mu.Lock()
+	channels := make([]string, 0, len(b.handlers))
+	for channel := range b.handlers {
+		channels = append(channels, channel)
+	}
+	listener := b.db.Listen(ctx, channels...)
+	b.listener = listener
+	b.mu.Unlock()
+
+	defer func() {
+		b.mu.Lock()
+		b.listener = nil
+		b.mu.Unlock()
+		listener.Close()
+	}()
+
+	notifications := listener.ChannelSize(notificationBuffer)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification, ok := <-notifications:
+			if !ok {
+				return
+			}
+			b.dispatch(notification)
+		}
+	}
+}
+
+func (b *PostgresBroker) dispatch(notification pg.Notification) {
+	b.mu.RLock()
+	handlers := b.handlers[notification.Channel]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler([]byte(notification.Payload))
+	}
+}
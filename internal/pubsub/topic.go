@@ -0,0 +1,101 @@
+// Package pubsub is a typed in-process publish/subscribe hub.
+package pubsub
+
+import (
+	"context"
+	"sync"
+
+	"github.com/shennawardana23/graphql-pba/internal/util/logger"
+)
+
+const DefaultBufferSize = 32
+
+// Topic fans every published event out to its subscribers. Each subscriber
+// has a bounded buffer; Publish never blocks, and a subscriber that lets its
+// buffer fill up is disconnected instead of slowing down the publisher.
+type Topic[T any] struct {
+	name       string
+	bufferSize int
+
+	mu          sync.RWMutex
+	subscribers map[*subscriber[T]]struct{}
+}
+
+type subscriber[T any] struct {
+	ch     chan T
+	filter func(T) bool
+	once   sync.Once
+}
+
+func (s *subscriber[T]) close() {
+	s.once.Do(func() { close(s.ch) })
+}
+
+func NewTopic[T any](name string, bufferSize int) *Topic[T] {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Topic[T]{
+		name:        name,
+		bufferSize:  bufferSize,
+		subscribers: make(map[*subscriber[T]]struct{}),
+	}
+}
+
+// Subscribe returns a channel receiving the events accepted by filter (all
+// events when filter is nil). The channel is closed when ctx is done or when
+// the subscriber falls too far behind.
+func (t *Topic[T]) Subscribe(ctx context.Context, filter func(T) bool) <-chan T {
+	sub := &subscriber[T]{
+		ch:     make(chan T, t.bufferSize),
+		filter: filter,
+	}
+
+	t.mu.Lock()
+	t.subscribers[sub] = struct{}{}
+	t.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		t.remove(sub)
+	}()
+
+	return sub.ch
+}
+
+// Publish delivers event to every matching subscriber without blocking
+func (t *Topic[T]) Publish(event T) {
+	var slow []*subscriber[T]
+
+	t.mu.RLock()
+	for sub := range t.subscribers {
+		if sub.filter != nil && !sub.filter(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	t.mu.RUnlock()
+
+	for _, sub := range slow {
+		logger.Log.WithField("topic", t.name).Warn("Disconnecting slow subscriber, buffer full")
+		t.remove(sub)
+	}
+}
+
+// Len returns the number of active subscribers
+func (t *Topic[T]) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.subscribers)
+}
+
+func (t *Topic[T]) remove(sub *subscriber[T]) {
+	t.mu.Lock()
+	delete(t.subscribers, sub)
+	t.mu.Unlock()
+	sub.close()
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/shennawardana23/graphql-pba/internal/entity"
+)
+
+// RestaurantFilter narrows a restaurant listing. Nil fields are ignored.
+type RestaurantFilter struct {
+	NameContains  *string
+	HasWebsite    *bool
+	HasWhatsapp   *bool
+	UserID        *int64
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+// UserFilter narrows a user listing. Nil fields are ignored.
+type UserFilter struct {
+	NameContains  *string
+	EmailContains *string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+var restaurantSortColumns = map[string]sortColumn[entity.Restaurant]{
+	"NAME":       {column: "restaurant_name", value: func(r entity.Restaurant) interface{} { return r.RestaurantName }},
+	"CREATED_AT": {column: "created_at", value: func(r entity.Restaurant) interface{} { return r.CreatedAt }},
+	"UPDATED_AT": {column: "updated_at", value: func(r entity.Restaurant) interface{} { return r.UpdatedAt }},
+}
+
+var userSortColumns = map[string]sortColumn[entity.User]{
+	"NAME":       {column: "name", value: func(u entity.User) interface{} { return u.Name }},
+	"EMAIL":      {column: "email", value: func(u entity.User) interface{} { return u.Email }},
+	"CREATED_AT": {column: "created_at", value: func(u entity.User) interface{} { return u.CreatedAt }},
+}
+
+func (f *RestaurantFilter) apply(q *orm.Query) *orm.Query {
+	if f == nil {
+		return q
+	}
+
+	if f.NameContains != nil {
+		q = q.Where("?TableAlias.restaurant_name ILIKE ?", containsPattern(*f.NameContains))
+	}
+	if f.HasWebsite != nil {
+		q = q.Where("(COALESCE(?TableAlias.restaurant_website, '') <> '') = ?", *f.HasWebsite)
+	}
+	if f.HasWhatsapp != nil {
+		q = q.Where("(COALESCE(?TableAlias.restaurant_whatsapp, '') <> '') = ?", *f.HasWhatsapp)
+	}
+	if f.UserID != nil {
+		q = q.Where("?TableAlias.user_id = ?", *f.UserID)
+	}
+	if f.CreatedAfter != nil {
+		q = q.Where("?TableAlias.created_at >= ?", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		q = q.Where("?TableAlias.created_at < ?", *f.CreatedBefore)
+	}
+	return q
+}
+
+func (f *UserFilter) apply(q *orm.Query) *orm.Query {
+	if f == nil {
+		return q
+	}
+
+	if f.NameContains != nil {
+		q = q.Where("?TableAlias.name ILIKE ?", containsPattern(*f.NameContains))
+	}
+	if f.EmailContains != nil {
+		q = q.Where("?TableAlias.email ILIKE ?", containsPattern(*f.EmailContains))
+	}
+	if f.CreatedAfter != nil {
+		q = q.Where("?TableAlias.created_at >= ?", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		q = q.Where("?TableAlias.created_at < ?", *f.CreatedBefore)
+	}
+	return q
+}
+
+// containsPattern escapes LIKE wildcards so user input only matches literally
+func containsPattern(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+	return "%" + s + "%"
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// PageArgs are the Relay connection arguments of a list query
+type PageArgs struct {
+	First  *int
+	After  *string
+	Last   *int
+	Before *string
+}
+
+// OrderBy selects the sort field of a list query. Field is one of the keys the
+// repository whitelists; an empty Field sorts by id.
+type OrderBy struct {
+	Field string
+	Desc  bool
+}
+
+// Page is one slice of a keyset-paginated list
+type Page[T any] struct {
+	Items           []T
+	Cursors         []string
+	HasNextPage     bool
+	HasPreviousPage bool
+	TotalCount      int
+}
+
+// sortColumn maps a whitelisted sort field to its column and row value
+type sortColumn[T any] struct {
+	column string
+	value  func(T) interface{}
+}
+
+// cursor is the decoded form of the opaque cursor handed to clients. It points
+// at the sort key and primary key of a row rather than an offset so it stays
+// valid while rows are inserted before it.
+type cursor struct {
+	Field string `json:"f,omitempty"`
+	Value string `json:"v,omitempty"`
+	ID    int64  `json:"id"`
+}
+
+func (c cursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(field, value string) (*cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, exception.NewValidationError(fmt.Sprintf("%s: invalid cursor", field))
+	}
+
+	var c cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID <= 0 {
+		return nil, exception.NewValidationError(fmt.Sprintf("%s: invalid cursor", field))
+	}
+	return &c, nil
+}
+
+// keyset holds the validated pagination window applied to a query
+type keyset[T any] struct {
+	limit    int
+	backward bool
+	desc     bool
+	field    string
+	sort     *sortColumn[T]
+	after    *cursor
+	before   *cursor
+}
+
+func newKeyset[T any](args PageArgs, order OrderBy, columns map[string]sortColumn[T]) (*keyset[T], error) {
+	if args.First != nil && args.Last != nil {
+		return nil, exception.NewValidationError("first and last must not be used together")
+	}
+
+	k := &keyset[T]{limit: DefaultPageSize, desc: order.Desc, field: order.Field}
+	switch {
+	case args.First != nil:
+		k.limit = *args.First
+	case args.Last != nil:
+		k.limit = *args.Last
+		k.backward = true
+	}
+	if k.limit < 0 || k.limit > MaxPageSize {
+		return nil, exception.NewValidationError(fmt.Sprintf("first/last: must be between 0 and %d", MaxPageSize))
+	}
+
+	if order.Field != "" {
+		sort, ok := columns[order.Field]
+		if !ok {
+			return nil, exception.NewValidationError(fmt.Sprintf("orderBy: unsupported field %s", order.Field))
+		}
+		k.sort = &sort
+	}
+
+	var err error
+	if args.After != nil {
+		if k.after, err = k.decode("after", *args.After); err != nil {
+			return nil, err
+		}
+	}
+	if args.Before != nil {
+		if k.before, err = k.decode("before", *args.Before); err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+// decode parses a cursor and rejects one issued for a different sort field
+func (k *keyset[T]) decode(arg, value string) (*cursor, error) {
+	c, err := decodeCursor(arg, value)
+	if err != nil {
+		return nil, err
+	}
+	if c.Field != k.field {
+		return nil, exception.NewValidationError(fmt.Sprintf("%s: cursor does not match orderBy", arg))
+	}
+	return c, nil
+}
+
+// apply restricts q to the window and fetches one extra row to detect
+// whether more rows exist past it
+func (k *keyset[T]) apply(q *orm.Query) *orm.Query {
+	if k.after != nil {
+		q = k.where(q, k.after, !k.desc)
+	}
+	if k.before != nil {
+		q = k.where(q, k.before, k.desc)
+	}
+
+	direction := "ASC"
+	if k.desc != k.backward {
+		direction = "DESC"
+	}
+	if k.sort != nil {
+		q = q.OrderExpr("?TableAlias.? "+direction, pg.Ident(k.sort.column))
+	}
+	return q.OrderExpr("?TableAlias.id " + direction).Limit(k.limit + 1)
+}
+
+// where keeps the rows sorting strictly after (greater) or before c
+func (k *keyset[T]) where(q *orm.Query, c *cursor, greater bool) *orm.Query {
+	op := "<"
+	if greater {
+		op = ">"
+	}
+
+	if k.sort == nil {
+		return q.Where("?TableAlias.id "+op+" ?", c.ID)
+	}
+	return q.Where("(?TableAlias.?, ?TableAlias.id) "+op+" (?, ?)", pg.Ident(k.sort.column), c.Value, c.ID)
+}
+
+func (k *keyset[T]) cursor(row T, id int64) string {
+	c := cursor{Field: k.field, ID: id}
+	if k.sort != nil {
+		switch v := k.sort.value(row).(type) {
+		case time.Time:
+			c.Value = v.UTC().Format(time.RFC3339Nano)
+		default:
+			c.Value = fmt.Sprint(v)
+		}
+	}
+	return c.encode()
+}
+
+// build trims the extra row, restores the requested order and fills in page info
+func (k *keyset[T]) build(rows []T, id func(T) int64, total int) *Page[T] {
+	hasMore := len(rows) > k.limit
+	if hasMore {
+		rows = rows[:k.limit]
+	}
+
+	if k.backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	page := &Page[T]{
+		Items:      rows,
+		Cursors:    make([]string, len(rows)),
+		TotalCount: total,
+	}
+	for i, row := range rows {
+		page.Cursors[i] = k.cursor(row, id(row))
+	}
+
+	if k.backward {
+		page.HasPreviousPage = hasMore
+		page.HasNextPage = k.before != nil
+	} else {
+		page.HasNextPage = hasMore
+		page.HasPreviousPage = k.after != nil
+	}
+	return page
+}
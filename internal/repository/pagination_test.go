@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/shennawardana23/graphql-pba/internal/entity"
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	for _, c := range []cursor{
+		{ID: 1},
+		{Field: "NAME", Value: "Warung 'Bu' Ani", ID: 42},
+		{Field: "CREATED_AT", Value: "2024-05-01T10:20:30.123456Z", ID: 7},
+	} {
+		decoded, err := decodeCursor("after", c.encode())
+		if err != nil {
+			t.Fatalf("decodeCursor(%+v): %v", c, err)
+		}
+		if *decoded != c {
+			t.Errorf("decodeCursor(encode(%+v)) = %+v", c, *decoded)
+		}
+	}
+}
+
+func TestKeysetCursor(t *testing.T) {
+	created := time.Date(2024, 5, 1, 17, 20, 30, 123456000, time.FixedZone("WIB", 7*3600))
+	restaurant := entity.Restaurant{ID: 9, RestaurantName: "Sate", CreatedAt: created}
+
+	k, err := newKeyset(PageArgs{}, OrderBy{Field: "CREATED_AT"}, restaurantSortColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := decodeCursor("after", k.cursor(restaurant, restaurant.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := cursor{Field: "CREATED_AT", Value: "2024-05-01T10:20:30.123456Z", ID: 9}
+	if *c != want {
+		t.Errorf("cursor = %+v, want %+v", *c, want)
+	}
+
+	// A cursor issued by a page is accepted back for the same order
+	encoded := k.cursor(restaurant, restaurant.ID)
+	if _, err := newKeyset(PageArgs{After: &encoded}, OrderBy{Field: "CREATED_AT"}, restaurantSortColumns); err != nil {
+		t.Errorf("newKeyset with its own cursor: %v", err)
+	}
+}
+
+func TestDecodeTamperedCursor(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := map[string]string{
+		"not base64":       "not a cursor!",
+		"padded base64":    base64.URLEncoding.EncodeToString([]byte(`{"id":1}`)),
+		"not JSON":         encode("id=1"),
+		"missing id":       encode(`{"f":"NAME","v":"x"}`),
+		"zero id":          encode(`{"id":0}`),
+		"negative id":      encode(`{"id":-3}`),
+		"id of wrong type": encode(`{"id":"1"}`),
+		"empty":            "",
+	}
+
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeCursor("after", value)
+			assertValidationError(t, err)
+		})
+	}
+}
+
+func TestNewKeysetRejects(t *testing.T) {
+	one, tooMany, negative := 1, MaxPageSize+1, -1
+	nameCursor := cursor{Field: "NAME", Value: "x", ID: 1}.encode()
+	idCursor := cursor{ID: 1}.encode()
+
+	tests := []struct {
+		name  string
+		args  PageArgs
+		order OrderBy
+	}{
+		{"first and last", PageArgs{First: &one, Last: &one}, OrderBy{}},
+		{"first too large", PageArgs{First: &tooMany}, OrderBy{}},
+		{"negative last", PageArgs{Last: &negative}, OrderBy{}},
+		{"unsupported field", PageArgs{}, OrderBy{Field: "EMAIL"}},
+		{"after cursor of another order", PageArgs{After: &nameCursor}, OrderBy{}},
+		{"before cursor of another order", PageArgs{Before: &idCursor}, OrderBy{Field: "NAME"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newKeyset(tt.args, tt.order, restaurantSortColumns)
+			assertValidationError(t, err)
+		})
+	}
+}
+
+func TestKeysetApply(t *testing.T) {
+	two := 2
+	named := cursor{Field: "NAME", Value: "Sate", ID: 5}.encode()
+	byID := cursor{ID: 5}.encode()
+
+	tests := []struct {
+		name  string
+		args  PageArgs
+		order OrderBy
+		want  string
+	}{
+		{
+			"first page by id",
+			PageArgs{},
+			OrderBy{},
+			`ORDER BY "restaurant".id ASC LIMIT 21`,
+		},
+		{
+			"first after, ASC",
+			PageArgs{First: &two, After: &byID},
+			OrderBy{},
+			`WHERE ("restaurant".id > 5) ORDER BY "restaurant".id ASC LIMIT 3`,
+		},
+		{
+			"first after, DESC",
+			PageArgs{First: &two, After: &byID},
+			OrderBy{Desc: true},
+			`WHERE ("restaurant".id < 5) ORDER BY "restaurant".id DESC LIMIT 3`,
+		},
+		{
+			"last before, ASC",
+			PageArgs{Last: &two, Before: &byID},
+			OrderBy{},
+			`WHERE ("restaurant".id < 5) ORDER BY "restaurant".id DESC LIMIT 3`,
+		},
+		{
+			"last before, DESC",
+			PageArgs{Last: &two, Before: &byID},
+			OrderBy{Desc: true},
+			`WHERE ("restaurant".id > 5) ORDER BY "restaurant".id ASC LIMIT 3`,
+		},
+		{
+			"first after by name, ASC",
+			PageArgs{First: &two, After: &named},
+			OrderBy{Field: "NAME"},
+			`WHERE (("restaurant"."restaurant_name", "restaurant".id) > ('Sate', 5)) ORDER BY "restaurant"."restaurant_name" ASC, "restaurant".id ASC LIMIT 3`,
+		},
+		{
+			"first after by name, DESC",
+			PageArgs{First: &two, After: &named},
+			OrderBy{Field: "NAME", Desc: true},
+			`WHERE (("restaurant"."restaurant_name", "restaurant".id) < ('Sate', 5)) ORDER BY "restaurant"."restaurant_name" DESC, "restaurant".id DESC LIMIT 3`,
+		},
+		{
+			"last before by name, ASC",
+			PageArgs{Last: &two, Before: &named},
+			OrderBy{Field: "NAME"},
+			`WHERE (("restaurant"."restaurant_name", "restaurant".id) < ('Sate', 5)) ORDER BY "restaurant"."restaurant_name" DESC, "restaurant".id DESC LIMIT 3`,
+		},
+		{
+			"last before by name, DESC",
+			PageArgs{Last: &two, Before: &named},
+			OrderBy{Field: "NAME", Desc: true},
+			`WHERE (("restaurant"."restaurant_name", "restaurant".id) > ('Sate', 5)) ORDER BY "restaurant"."restaurant_name" ASC, "restaurant".id ASC LIMIT 3`,
+		},
+		{
+			"after and before, DESC",
+			PageArgs{After: &byID, Before: &byID},
+			OrderBy{Desc: true},
+			`WHERE ("restaurant".id < 5) AND ("restaurant".id > 5) ORDER BY "restaurant".id DESC LIMIT 21`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := newKeyset(tt.args, tt.order, restaurantSortColumns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := renderWindow(t, k.apply(orm.NewQuery(nil, (*entity.Restaurant)(nil)))); got != tt.want {
+				t.Errorf("window\n got %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeysetBuild(t *testing.T) {
+	two := 2
+	c := cursor{ID: 10}.encode()
+	rows := func(ids ...int64) []entity.Restaurant {
+		restaurants := make([]entity.Restaurant, len(ids))
+		for i, id := range ids {
+			restaurants[i].ID = id
+		}
+		return restaurants
+	}
+
+	tests := []struct {
+		name         string
+		args         PageArgs
+		rows         []entity.Restaurant
+		wantIDs      []int64
+		wantNext     bool
+		wantPrevious bool
+	}{
+		{"forward with more", PageArgs{First: &two}, rows(1, 2, 3), []int64{1, 2}, true, false},
+		{"forward last page after a cursor", PageArgs{First: &two, After: &c}, rows(11), []int64{11}, false, true},
+		{"backward with more", PageArgs{Last: &two, Before: &c}, rows(9, 8, 7), []int64{8, 9}, true, true},
+		{"backward first page", PageArgs{Last: &two}, rows(9, 8), []int64{8, 9}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := newKeyset(tt.args, OrderBy{}, restaurantSortColumns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			page := k.build(tt.rows, func(r entity.Restaurant) int64 { return r.ID }, 42)
+
+			var ids []int64
+			for _, r := range page.Items {
+				ids = append(ids, r.ID)
+			}
+			if len(ids) != len(tt.wantIDs) {
+				t.Fatalf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Fatalf("ids = %v, want %v", ids, tt.wantIDs)
+				}
+				if got, _ := decodeCursor("after", page.Cursors[i]); got == nil || got.ID != ids[i] {
+					t.Errorf("cursor %d = %+v, want id %d", i, got, ids[i])
+				}
+			}
+			if page.HasNextPage != tt.wantNext || page.HasPreviousPage != tt.wantPrevious {
+				t.Errorf("hasNext, hasPrevious = %v, %v; want %v, %v", page.HasNextPage, page.HasPreviousPage, tt.wantNext, tt.wantPrevious)
+			}
+			if page.TotalCount != 42 {
+				t.Errorf("TotalCount = %d, want 42", page.TotalCount)
+			}
+		})
+	}
+}
+
+// renderWindow formats q and returns its clauses following the FROM clause
+func renderWindow(t *testing.T, q *orm.Query) string {
+	t.Helper()
+	b, err := orm.NewSelectQuery(q).AppendQuery(orm.NewFormatter().WithModel(q), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, window, ok := strings.Cut(string(b), `FROM "restaurants" AS "restaurant" `)
+	if !ok {
+		t.Fatalf("unexpected query %s", b)
+	}
+	return window
+}
+
+func assertValidationError(t *testing.T, err error) {
+	t.Helper()
+	var customErr *exception.CustomError
+	if !errors.As(err, &customErr) || customErr.Code != "VALIDATION_ERROR" {
+		t.Errorf("err = %v, want a VALIDATION_ERROR", err)
+	}
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/shennawardana23/graphql-pba/internal/app/database"
+	"github.com/shennawardana23/graphql-pba/internal/entity"
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+)
+
+type PersistedQueryRepository struct {
+	db *pg.DB
+}
+
+func NewPersistedQueryRepository(db *pg.DB) *PersistedQueryRepository {
+	return &PersistedQueryRepository{db: db}
+}
+
+func (r *PersistedQueryRepository) WithContext(ctx context.Context) *database.TracedDB {
+	return database.Trace(ctx, r.db)
+}
+
+func (r *PersistedQueryRepository) FindByHash(ctx context.Context, hash string) (*entity.PersistedQuery, error) {
+	query := new(entity.PersistedQuery)
+	err := r.WithContext(ctx).
+		Model(query).
+		Where("hash = ?", hash).
+		Select()
+
+	if err == pg.ErrNoRows {
+		return nil, nil
+	}
+	return query, exception.TranslatePostgresError(ctx, err)
+}
+
+// Save stores a document once while the table holds fewer than maxRows
+// documents; saving a known hash is a no-op. It reports whether the document
+// is stored.
+func (r *PersistedQueryRepository) Save(ctx context.Context, query *entity.PersistedQuery, maxRows int) (bool, error) {
+	query.CreatedAt = time.Now()
+
+	res, err := r.WithContext(ctx).ExecContext(ctx, `
+		INSERT INTO persisted_queries (hash, query, created_at)
+		SELECT ?, ?, ?
+		WHERE (SELECT count(*) FROM persisted_queries) < ?
+		ON CONFLICT (hash) DO NOTHING`,
+		query.Hash, query.Query, query.CreatedAt, maxRows)
+	if err != nil {
+		return false, exception.TranslatePostgresError(ctx, err)
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+// DeleteCreatedBefore removes the documents registered before t
+func (r *PersistedQueryRepository) DeleteCreatedBefore(ctx context.Context, t time.Time) (int, error) {
+	res, err := r.WithContext(ctx).
+		ModelContext(ctx, (*entity.PersistedQuery)(nil)).
+		Where("created_at < ?", t).
+		Delete()
+	if err != nil {
+		return 0, exception.TranslatePostgresError(ctx, err)
+	}
+	return res.RowsAffected(), nil
+}
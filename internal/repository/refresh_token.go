@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/shennawardana23/graphql-pba/internal/app/database"
+	"github.com/shennawardana23/graphql-pba/internal/entity"
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+)
+
+type RefreshTokenRepository struct {
+	db *pg.DB
+}
+
+func NewRefreshTokenRepository(db *pg.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (r *RefreshTokenRepository) WithContext(ctx context.Context) *database.TracedDB {
+	return database.Trace(ctx, r.db)
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
+	token.CreatedAt = time.Now()
+
+	_, err := r.WithContext(ctx).ModelContext(ctx, token).Insert()
+	return exception.TranslatePostgresError(ctx, err)
+}
+
+func (r *RefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	token := new(entity.RefreshToken)
+	err := r.WithContext(ctx).
+		Model(token).
+		Where("token_hash = ?", tokenHash).
+		Select()
+
+	if err == pg.ErrNoRows {
+		return nil, nil
+	}
+	return token, exception.TranslatePostgresError(ctx, err)
+}
+
+// Revoke marks a single token as used; it returns false when the token was
+// already revoked so callers can detect replays
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, id int64) (bool, error) {
+	res, err := r.WithContext(ctx).
+		Model((*entity.RefreshToken)(nil)).
+		Set("revoked_at = ?", time.Now()).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Update()
+	if err != nil {
+		return false, exception.TranslatePostgresError(ctx, err)
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID int64) error {
+	_, err := r.WithContext(ctx).
+		Model((*entity.RefreshToken)(nil)).
+		Set("revoked_at = ?", time.Now()).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Update()
+	return exception.TranslatePostgresError(ctx, err)
+}
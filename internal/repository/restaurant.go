@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/go-pg/pg/v10"
+	"github.com/shennawardana23/graphql-pba/internal/app/database"
 	"github.com/shennawardana23/graphql-pba/internal/entity"
 	"github.com/shennawardana23/graphql-pba/internal/util/exception"
 )
@@ -17,21 +18,37 @@ func NewRestaurantRepository(db *pg.DB) *RestaurantRepository {
 	return &RestaurantRepository{db: db}
 }
 
-func (r *RestaurantRepository) WithContext(ctx context.Context) *pg.DB {
-	return r.db.WithContext(ctx)
+func (r *RestaurantRepository) WithContext(ctx context.Context) *database.TracedDB {
+	return database.Trace(ctx, r.db)
 }
 
-func (r *RestaurantRepository) FindAll(ctx context.Context) ([]entity.Restaurant, error) {
+// FindPage returns one keyset-paginated page of restaurants matching filter
+func (r *RestaurantRepository) FindPage(ctx context.Context, args PageArgs, filter *RestaurantFilter, order OrderBy) (*Page[entity.Restaurant], error) {
+	k, err := newKeyset(args, order, restaurantSortColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := filter.apply(r.WithContext(ctx).ModelContext(ctx, (*entity.Restaurant)(nil))).Count()
+	if err != nil {
+		return nil, exception.TranslatePostgresError(ctx, err)
+	}
+
 	var restaurants []entity.Restaurant
-	err := r.WithContext(ctx).ModelContext(ctx, &restaurants).Select()
-	return restaurants, exception.TranslatePostgresError(ctx, err)
+	if k.limit > 0 {
+		err = k.apply(filter.apply(r.WithContext(ctx).ModelContext(ctx, &restaurants))).Select()
+		if err != nil {
+			return nil, exception.TranslatePostgresError(ctx, err)
+		}
+	}
+
+	return k.build(restaurants, func(r entity.Restaurant) int64 { return r.ID }, total), nil
 }
 
 func (r *RestaurantRepository) FindByID(ctx context.Context, id int64) (*entity.Restaurant, error) {
 	restaurant := &entity.Restaurant{ID: id}
 	err := r.WithContext(ctx).
 		Model(restaurant).
-		Relation("User").
 		WherePK().
 		Select()
 	if err == pg.ErrNoRows {
@@ -40,6 +57,17 @@ func (r *RestaurantRepository) FindByID(ctx context.Context, id int64) (*entity.
 	return restaurant, exception.TranslatePostgresError(ctx, err)
 }
 
+// FindByUserIDs loads the restaurants owned by any of userIDs with a single query
+func (r *RestaurantRepository) FindByUserIDs(ctx context.Context, userIDs []int64) ([]entity.Restaurant, error) {
+	var restaurants []entity.Restaurant
+	err := r.WithContext(ctx).
+		ModelContext(ctx, &restaurants).
+		Where("user_id IN (?)", pg.In(userIDs)).
+		Order("id ASC").
+		Select()
+	return restaurants, exception.TranslatePostgresError(ctx, err)
+}
+
 func (r *RestaurantRepository) Create(ctx context.Context, restaurant *entity.Restaurant) error {
 	restaurant.CreatedAt = time.Now()
 	restaurant.UpdatedAt = time.Now()
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/go-pg/pg/v10"
+	"github.com/shennawardana23/graphql-pba/internal/app/database"
 	"github.com/shennawardana23/graphql-pba/internal/entity"
 	"github.com/shennawardana23/graphql-pba/internal/util/exception"
 )
@@ -17,14 +18,31 @@ func NewUserRepository(db *pg.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-func (r *UserRepository) WithContext(ctx context.Context) *pg.DB {
-	return r.db.WithContext(ctx)
+func (r *UserRepository) WithContext(ctx context.Context) *database.TracedDB {
+	return database.Trace(ctx, r.db)
 }
 
-func (r *UserRepository) FindAll(ctx context.Context) ([]entity.User, error) {
+// FindPage returns one keyset-paginated page of users matching filter
+func (r *UserRepository) FindPage(ctx context.Context, args PageArgs, filter *UserFilter, order OrderBy) (*Page[entity.User], error) {
+	k, err := newKeyset(args, order, userSortColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := filter.apply(r.WithContext(ctx).ModelContext(ctx, (*entity.User)(nil))).Count()
+	if err != nil {
+		return nil, exception.TranslatePostgresError(ctx, err)
+	}
+
 	var users []entity.User
-	err := r.WithContext(ctx).ModelContext(ctx, &users).Select()
-	return users, exception.TranslatePostgresError(ctx, err)
+	if k.limit > 0 {
+		err = k.apply(filter.apply(r.WithContext(ctx).ModelContext(ctx, &users))).Select()
+		if err != nil {
+			return nil, exception.TranslatePostgresError(ctx, err)
+		}
+	}
+
+	return k.build(users, func(u entity.User) int64 { return u.ID }, total), nil
 }
 
 func (r *UserRepository) FindByID(ctx context.Context, id int64) (*entity.User, error) {
@@ -36,6 +54,16 @@ func (r *UserRepository) FindByID(ctx context.Context, id int64) (*entity.User,
 	return user, exception.TranslatePostgresError(ctx, err)
 }
 
+// FindByIDs loads every user whose id is in ids with a single query
+func (r *UserRepository) FindByIDs(ctx context.Context, ids []int64) ([]entity.User, error) {
+	var users []entity.User
+	err := r.WithContext(ctx).
+		ModelContext(ctx, &users).
+		Where("id IN (?)", pg.In(ids)).
+		Select()
+	return users, exception.TranslatePostgresError(ctx, err)
+}
+
 func (r *UserRepository) Create(ctx context.Context, user *entity.User) error {
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
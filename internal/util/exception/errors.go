@@ -3,6 +3,8 @@ package exception
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"runtime/debug"
 	"strings"
 
 	"github.com/go-pg/pg/v10"
@@ -18,124 +20,187 @@ func PanicOnError(err error) {
 
 func PanicOnErrorContext(ctx context.Context, err error) {
 	if err != nil {
-		logger.Error(ctx, err)
+		logger.ErrorContext(ctx, err)
 		panic(err)
 	}
 }
 
+// CustomError is an error whose code, message and details are shown to
+// clients. The cause it wraps is only logged.
 type CustomError struct {
 	Code    string
 	Message string
 	Details string
+
+	// FieldErrors lists the input fields that failed validation
+	FieldErrors []FieldError
+
+	Cause error
+	stack []byte
+}
+
+// FieldError is one failed validation rule of an input field, returned in
+// extensions.fieldErrors
+type FieldError struct {
+	// Field is the GraphQL input path, e.g. input.email
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
 }
 
 func (e *CustomError) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
 	return e.Message
 }
 
-var (
-	ErrDuplicateEmail = &CustomError{
-		Code:    "USER_EMAIL_EXISTS",
-		Message: "Email address is already in use",
-		Details: "Please use a different email address",
-	}
+func (e *CustomError) Unwrap() error {
+	return e.Cause
+}
 
-	ErrInvalidInput = &CustomError{
-		Code:    "INVALID_INPUT",
-		Message: "Invalid input provided",
-		Details: "Please check your input and try again",
-	}
+// Is reports errors with the same code as equal, so errors.Is(err,
+// ErrNotFound) holds for every wrapped copy of ErrNotFound
+func (e *CustomError) Is(target error) bool {
+	t, ok := target.(*CustomError)
+	return ok && t.Code == e.Code
+}
 
-	ErrNotFound = &CustomError{
-		Code:    "NOT_FOUND",
-		Message: "Resource not found",
-		Details: "The requested resource does not exist",
-	}
+// Wrap returns a copy of e caused by cause, recording the stack of the caller
+func (e *CustomError) Wrap(cause error) *CustomError {
+	return &CustomError{
+		Code:    e.Code,
+		Message: e.Message,
+		Details: e.Details,
+
+		FieldErrors: e.FieldErrors,
 
-	ErrInternalServer = &CustomError{
-		Code:    "INTERNAL_SERVER_ERROR",
-		Message: "Internal server error",
-		Details: "An unexpected error occurred",
+		Cause: cause,
+		stack: debug.Stack(),
 	}
+}
+
+// Stack returns the stack recorded by Wrap, nil for errors never wrapped
+func (e *CustomError) Stack() []byte {
+	return e.stack
+}
+
+// WithDetails returns a copy of e with details, for codes whose details are
+// built at runtime
+func (e *CustomError) WithDetails(details string) *CustomError {
+	err := *e
+	err.Details = details
+	return &err
+}
+
+var (
+	ErrDuplicateEmail           = New(CodeUserEmailExists)
+	ErrInvalidInput             = New(CodeInvalidInput)
+	ErrNotFound                 = New(CodeNotFound)
+	ErrInternalServer           = New(CodeInternalServerError)
+	ErrInvalidCredential        = New(CodeInvalidCredential)
+	ErrUnauthorized             = New(CodeUnauthorized)
+	ErrForbidden                = New(CodeForbidden)
+	ErrPersistedQueryNotAllowed = New(CodePersistedQueryNotAllowed)
 )
 
-func NewCustomError(code, message, details string) *CustomError {
-	return &CustomError{
-		Code:    code,
-		Message: message,
-		Details: details,
+// TranslateTokenError maps the token sentinel errors to client-facing errors
+func TranslateTokenError(err error) *CustomError {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrTokenExpired):
+		return New(CodeTokenExpired)
+	case errors.Is(err, ErrTokenInvalid):
+		return New(CodeTokenInvalid)
+	default:
+		return ErrUnauthorized
 	}
 }
 
 func NewValidationError(details string) *CustomError {
-	return &CustomError{
-		Code:    "VALIDATION_ERROR",
-		Message: "Validation failed",
-		Details: details,
-	}
+	return New(CodeValidationError).WithDetails(details)
 }
 
+// NewFieldValidationError reports the failed rules of every field, details
+// sums them up for clients that do not read fieldErrors
+func NewFieldValidationError(details string, fields []FieldError) *CustomError {
+	err := NewValidationError(details)
+	err.FieldErrors = fields
+	return err
+}
+
+// TranslatePostgresError maps database errors, and the sentinel errors of
+// this package, to client-facing errors wrapping them
 func TranslatePostgresError(ctx context.Context, err error) error {
 	if err == nil {
 		return nil
 	}
 
 	var customErr *CustomError
+	if errors.As(err, &customErr) {
+		return err
+	}
 
 	switch {
-	case err == sql.ErrNoRows || err == pg.ErrNoRows:
+	case errors.Is(err, sql.ErrNoRows) || errors.Is(err, pg.ErrNoRows) || errors.Is(err, ErrEmptyResult):
 		customErr = ErrNotFound
 
-	case isPgError(err, "23505"): // unique_violation
+	case isPgError(err, "23505") || errors.Is(err, ErrUniqueViolation) || errors.Is(err, ErrDupeKey):
 		if strings.Contains(err.Error(), "idx_users_email") {
 			customErr = ErrDuplicateEmail
 		} else {
-			customErr = NewCustomError(
-				"DUPLICATE_ENTRY",
-				"Duplicate entry found",
-				"A record with this value already exists",
-			)
+			customErr = New(CodeDuplicateEntry)
 		}
 
-	case isPgError(err, "23503"): // foreign_key_violation
-		customErr = NewCustomError(
-			"FOREIGN_KEY_VIOLATION",
-			"Invalid reference",
-			"The referenced record does not exist",
-		)
+	case isPgError(err, "23503") || errors.Is(err, ErrForeignKeyViolation):
+		customErr = New(CodeForeignKeyViolation)
 
 	case isPgError(err, "23502"): // not_null_violation
-		customErr = NewCustomError(
-			"REQUIRED_FIELD",
-			"Required field missing",
-			"Please provide all required fields",
-		)
+		customErr = New(CodeRequiredField)
+
+	case isPgError(err, "55P03") || isPgError(err, "40P01") || errors.Is(err, ErrUnableToLock):
+		// lock_not_available, deadlock_detected
+		customErr = New(CodeDataLocked)
+
+	case errors.Is(err, ErrInvalidRequest) || errors.Is(err, ErrFailedReadEnum):
+		customErr = New(CodeInvalidRequest)
+
+	case errors.Is(err, ErrMethodNotAllowed):
+		customErr = New(CodeMethodNotAllowed)
+
+	case errors.Is(err, ErrUnauthorizedMethod):
+		customErr = ErrUnauthorized
+
+	case errors.Is(err, ErrTokenExpired) || errors.Is(err, ErrTokenInvalid):
+		customErr = TranslateTokenError(err)
 
 	default:
-		logger.Error(ctx, err)
+		// Logged with an error id by the error presenter
 		customErr = ErrInternalServer
 	}
 
-	return customErr
+	return customErr.Wrap(err)
 }
 
 // isPgError checks if the error is a postgres error with the given code
 func isPgError(err error, code string) bool {
-	pgErr, ok := err.(pg.Error)
-	return ok && pgErr.Field('C') == code
+	var pgErr pg.Error
+	return errors.As(err, &pgErr) && pgErr.Field('C') == code
 }
 
 func CancelBackground(ctx context.Context, cancel context.CancelFunc, errorMessage string, successMessage string) {
 	select {
 	case <-ctx.Done():
 		if len(errorMessage) > 0 {
-			logger.Error(ctx, errorMessage)
+			logger.ErrorContext(ctx, errorMessage)
 		}
 		cancel()
 		return
 	default:
 		if len(successMessage) > 0 {
-			logger.Info(ctx, successMessage)
+			logger.InfoContext(ctx, successMessage)
 		}
 		return
 	}
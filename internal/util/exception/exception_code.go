@@ -51,6 +51,25 @@ const (
 	CodeInvalidFormat        = "INVALID_FORMAT"
 	CodeUnauthorized         = "UNAUTHORIZED"
 	CodeMethodNotAllowed     = "METHOD_NOT_ALLOWED"
+
+	CodeUserEmailExists     = "USER_EMAIL_EXISTS"
+	CodeInvalidInput        = "INVALID_INPUT"
+	CodeNotFound            = "NOT_FOUND"
+	CodeValidationError     = "VALIDATION_ERROR"
+	CodeDuplicateEntry      = "DUPLICATE_ENTRY"
+	CodeForeignKeyViolation = "FOREIGN_KEY_VIOLATION"
+	CodeRequiredField       = "REQUIRED_FIELD"
+	CodeInvalidID           = "INVALID_ID"
+
+	CodePersistedQueryNotAllowed = "PERSISTED_QUERY_NOT_ALLOWED"
+	CodeQueryTooDeep             = "QUERY_TOO_DEEP"
+	CodeQueryTooManyAliases      = "QUERY_TOO_MANY_ALIASES"
+	CodeQueryTooComplex          = "QUERY_TOO_COMPLEX"
+
+	// Raised by gqlgen itself
+	CodeGraphQLParseFailed      = "GRAPHQL_PARSE_FAILED"
+	CodeGraphQLValidationFailed = "GRAPHQL_VALIDATION_FAILED"
+	CodePersistedQueryNotFound  = "PERSISTED_QUERY_NOT_FOUND"
 )
 
 type (
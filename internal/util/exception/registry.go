@@ -0,0 +1,150 @@
+package exception
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/shennawardana23/graphql-pba/internal/util/i18n"
+)
+
+// Severity tells how alarming an error code is, the access log line of an
+// operation failing with it is written at the matching level
+type Severity string
+
+const (
+	// SeverityInfo is an expected outcome of bad client input
+	SeverityInfo Severity = "INFO"
+	// SeverityWarning hints at misuse: failed authentication, limits hit
+	SeverityWarning Severity = "WARNING"
+	// SeverityError is a server side failure
+	SeverityError Severity = "ERROR"
+)
+
+// Definition is the registered meaning of an error code
+type Definition struct {
+	Code       string
+	Message    string
+	Details    string
+	HTTPStatus int
+	Severity   Severity
+	// Retryable tells clients the same request may succeed later
+	Retryable bool
+}
+
+// registry is built during variable initialization, before the Err*
+// variables created from it
+var registry = newRegistry(
+	// Input
+	Definition{CodeInvalidInput, "Invalid input provided", "Please check your input and try again", http.StatusBadRequest, SeverityInfo, false},
+	Definition{CodeValidationError, "Validation failed", "Please check the fields listed in fieldErrors", http.StatusBadRequest, SeverityInfo, false},
+	Definition{CodeInvalidValidation, "Invalid data", "Please check your input and try again", http.StatusBadRequest, SeverityInfo, false},
+	Definition{CodeInvalidRequest, "Invalid request", "The request could not be understood", http.StatusBadRequest, SeverityInfo, false},
+	Definition{CodeBadRequest, "Bad request", "The request is malformed", http.StatusBadRequest, SeverityInfo, false},
+	Definition{CodeInvalidData, "Invalid data", "The data provided is not valid", http.StatusBadRequest, SeverityInfo, false},
+	Definition{CodeInvalidFormat, "Invalid format", "Please check the format of your input", http.StatusBadRequest, SeverityInfo, false},
+	Definition{CodeInvalidAge, "Invalid age", "The age does not meet the requirements", http.StatusBadRequest, SeverityInfo, false},
+	Definition{CodeInvalidID, "Invalid user ID", "User ID must be a positive number", http.StatusBadRequest, SeverityInfo, false},
+	Definition{CodeRequiredField, "Required field missing", "Please provide all required fields", http.StatusBadRequest, SeverityInfo, false},
+	Definition{CodeMissingRequiredData, "Missing required data", "Please provide all required data", http.StatusBadRequest, SeverityInfo, false},
+	Definition{CodeDateExpired, "Date expired", "The date has already passed", http.StatusBadRequest, SeverityInfo, false},
+	Definition{CodeFileTypeNotSupported, "File type not supported", "Please upload a supported file type", http.StatusUnsupportedMediaType, SeverityInfo, false},
+
+	// Data
+	Definition{CodeNotFound, "Resource not found", "The requested resource does not exist", http.StatusNotFound, SeverityInfo, false},
+	Definition{CodeDataNotFound, "Data not found", "The requested data does not exist", http.StatusNotFound, SeverityInfo, false},
+	Definition{CodeUserEmailExists, "Email address is already in use", "Please use a different email address", http.StatusConflict, SeverityInfo, false},
+	Definition{CodeEmailExist, "Email address already exists", "Please use a different email address", http.StatusConflict, SeverityInfo, false},
+	Definition{CodeDuplicateEntry, "Duplicate entry found", "A record with this value already exists", http.StatusConflict, SeverityInfo, false},
+	Definition{CodeDataAlreadyExist, "Data already exists", "A record with this data already exists", http.StatusConflict, SeverityInfo, false},
+	Definition{CodeForeignKeyViolation, "Invalid reference", "The referenced record does not exist", http.StatusUnprocessableEntity, SeverityInfo, false},
+	Definition{CodeConflict, "Conflict", "The resource was changed by another request, please retry", http.StatusConflict, SeverityInfo, true},
+	Definition{CodeDataLocked, "Data is locked", "The data is being changed, please retry shortly", http.StatusLocked, SeverityWarning, true},
+	Definition{CodeFileNotProcessedYet, "File not processed yet", "Please retry once processing has finished", http.StatusConflict, SeverityInfo, true},
+
+	// Authentication and accounts
+	Definition{CodeUnauthorized, "Authentication required", "Please provide a valid access token", http.StatusUnauthorized, SeverityInfo, false},
+	Definition{CodeInvalidCredential, "Invalid email or password", "Please check your credentials and try again", http.StatusUnauthorized, SeverityWarning, false},
+	Definition{CodeTokenExpired, "Token expired", "Please refresh your access token", http.StatusUnauthorized, SeverityInfo, false},
+	Definition{CodeTokenInvalid, "Token invalid", "The provided token is not valid", http.StatusUnauthorized, SeverityWarning, false},
+	Definition{CodeForbidden, "Access denied", "You do not have permission to perform this action", http.StatusForbidden, SeverityWarning, false},
+	Definition{CodeMethodNotAllowed, "Method not allowed", "This method is not supported", http.StatusMethodNotAllowed, SeverityInfo, false},
+	Definition{CodeAccountLocked, "Account locked", "Too many failed attempts, please try again later", http.StatusLocked, SeverityWarning, true},
+	Definition{CodeAccountBlocked, "Account blocked", "Please contact support", http.StatusForbidden, SeverityWarning, false},
+	Definition{CodeAccountDeleted, "Account deleted", "This account no longer exists", http.StatusGone, SeverityInfo, false},
+	Definition{CodeEmailNotVerified, "Email not verified", "Please verify your email address first", http.StatusForbidden, SeverityInfo, false},
+	Definition{CodeOtpFailed, "OTP verification failed", "Please request a new code", http.StatusBadRequest, SeverityWarning, false},
+	Definition{CodeOtpInvalid, "Invalid OTP", "Please check the code and try again", http.StatusBadRequest, SeverityInfo, false},
+
+	// Limits
+	Definition{CodeRequestTooFast, "Too many requests", "Please slow down and retry later", http.StatusTooManyRequests, SeverityWarning, true},
+	Definition{CodeQuotaLimitReached, "Operation exceeds the rate limit budget", "Select fewer fields and lists in a single operation", http.StatusTooManyRequests, SeverityWarning, false},
+	Definition{CodeQueryTooDeep, "Query is too deep", "Reduce the nesting of the operation", http.StatusBadRequest, SeverityWarning, false},
+	Definition{CodeQueryTooManyAliases, "Query uses too many aliases", "Reduce the number of aliases in the operation", http.StatusBadRequest, SeverityWarning, false},
+	Definition{CodeQueryTooComplex, "Query is too complex", "Select fewer fields or smaller pages", http.StatusBadRequest, SeverityWarning, false},
+
+	// GraphQL protocol
+	Definition{CodeGraphQLParseFailed, "Invalid GraphQL document", "The document could not be parsed", http.StatusUnprocessableEntity, SeverityInfo, false},
+	Definition{CodeGraphQLValidationFailed, "Invalid GraphQL operation", "The operation does not match the schema", http.StatusUnprocessableEntity, SeverityInfo, false},
+	Definition{CodePersistedQueryNotFound, "PersistedQueryNotFound", "Send the full document along with its hash", http.StatusOK, SeverityInfo, true},
+	Definition{CodePersistedQueryNotAllowed, "Operation is not allowed", "Only registered operations can be executed, send the sha256Hash of a known document", http.StatusBadRequest, SeverityWarning, false},
+
+	// Server
+	Definition{CodeInternalServerError, "Internal server error", "An unexpected error occurred", http.StatusInternalServerError, SeverityError, false},
+	Definition{CodeServiceUnavailable, "Service unavailable", "Please try again later", http.StatusServiceUnavailable, SeverityError, true},
+	Definition{CodeRequestFailed, "Request failed", "An upstream request failed, please try again", http.StatusBadGateway, SeverityError, true},
+)
+
+func newRegistry(definitions ...Definition) map[string]Definition {
+	registry := make(map[string]Definition, len(definitions))
+	for _, d := range definitions {
+		if _, ok := registry[d.Code]; ok {
+			panic("exception: code registered twice: " + d.Code)
+		}
+		registry[d.Code] = d
+	}
+	return registry
+}
+
+// The registered texts are the English messages of the catalogue
+func init() {
+	for code, d := range registry {
+		i18n.Add(i18n.English, "error."+code+".message", d.Message)
+		i18n.Add(i18n.English, "error."+code+".details", d.Details)
+	}
+}
+
+// Lookup returns the definition of code
+func Lookup(code string) (Definition, bool) {
+	d, ok := registry[code]
+	return d, ok
+}
+
+// Definitions returns every registered code, sorted by code
+func Definitions() []Definition {
+	definitions := make([]Definition, 0, len(registry))
+	for _, d := range registry {
+		definitions = append(definitions, d)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Code < definitions[j].Code
+	})
+	return definitions
+}
+
+// New returns an error with the registered message and details of code.
+// Unregistered codes are a programming error and panic.
+func New(code string) *CustomError {
+	d, ok := registry[code]
+	if !ok {
+		panic("exception: unregistered code " + code)
+	}
+	return &CustomError{Code: d.Code, Message: d.Message, Details: d.Details}
+}
+
+// HTTPStatus returns the status registered for code, 500 for unknown codes
+func HTTPStatus(code string) int {
+	if d, ok := registry[code]; ok {
+		return d.HTTPStatus
+	}
+	return http.StatusInternalServerError
+}
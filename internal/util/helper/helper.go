@@ -7,3 +7,20 @@ func Int64ToIntPtr(i *int64) *int {
 	val := int(*i)
 	return &val
 }
+
+// IntToInt64Ptr converts an optional int, keeping nil
+func IntToInt64Ptr(i *int) *int64 {
+	if i == nil {
+		return nil
+	}
+	val := int64(*i)
+	return &val
+}
+
+// StringToPtr returns a pointer to s, or nil for an empty string
+func StringToPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
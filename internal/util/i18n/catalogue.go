@@ -0,0 +1,123 @@
+package i18n
+
+// catalogue holds the messages of every locale. Error messages are keyed by
+// error code, validation messages by validator tag; {0} is the rule param.
+// English error messages are registered by the exception package.
+var catalogue = map[string]map[string]string{
+	English: {
+		"validation.required": "must be provided",
+		"validation.email":    "must be a valid email address",
+		"validation.min":      "must be at least {0} characters",
+		"validation.max":      "must not exceed {0} characters",
+		"validation.oneof":    "must be one of: {0}",
+		"validation.http_url": "must be a valid http or https URL",
+		"validation.phone":    "must be a valid phone number, e.g. +6281234567890 or 081234567890",
+		"validation.whatsapp": "must be a valid WhatsApp mobile number, e.g. +6281234567890",
+		"validation.default":  "failed validation: {0}",
+	},
+	Indonesian: {
+		"error.USER_EMAIL_EXISTS.message":           "Alamat email sudah digunakan",
+		"error.USER_EMAIL_EXISTS.details":           "Silakan gunakan alamat email lain",
+		"error.INVALID_INPUT.message":               "Input tidak valid",
+		"error.INVALID_INPUT.details":               "Silakan periksa input Anda dan coba lagi",
+		"error.NOT_FOUND.message":                   "Data tidak ditemukan",
+		"error.NOT_FOUND.details":                   "Data yang diminta tidak ada",
+		"error.INTERNAL_SERVER_ERROR.message":       "Terjadi kesalahan pada server",
+		"error.INTERNAL_SERVER_ERROR.details":       "Terjadi kesalahan yang tidak terduga",
+		"error.INVALID_CREDENTIAL.message":          "Email atau kata sandi salah",
+		"error.INVALID_CREDENTIAL.details":          "Silakan periksa kembali kredensial Anda",
+		"error.UNAUTHORIZED.message":                "Autentikasi diperlukan",
+		"error.UNAUTHORIZED.details":                "Silakan sertakan token akses yang valid",
+		"error.FORBIDDEN.message":                   "Akses ditolak",
+		"error.FORBIDDEN.details":                   "Anda tidak memiliki izin untuk melakukan tindakan ini",
+		"error.PERSISTED_QUERY_NOT_ALLOWED.message": "Operasi tidak diizinkan",
+		"error.PERSISTED_QUERY_NOT_ALLOWED.details": "Hanya operasi terdaftar yang dapat dijalankan, kirim sha256Hash dari dokumen yang dikenal",
+		"error.TOKEN_EXPIRED.message":               "Token kedaluwarsa",
+		"error.TOKEN_EXPIRED.details":               "Silakan perbarui token akses Anda",
+		"error.TOKEN_INVALID.message":               "Token tidak valid",
+		"error.TOKEN_INVALID.details":               "Token yang diberikan tidak valid",
+		"error.VALIDATION_ERROR.message":            "Validasi gagal",
+		"error.VALIDATION_ERROR.details":            "Silakan periksa kolom yang tercantum di fieldErrors",
+		"error.INVALID_VALIDATION.message":          "Data tidak valid",
+		"error.INVALID_VALIDATION.details":          "Silakan periksa input Anda dan coba lagi",
+		"error.INVALID_REQUEST.message":             "Permintaan tidak valid",
+		"error.INVALID_REQUEST.details":             "Permintaan tidak dapat dipahami",
+		"error.BAD_REQUEST.message":                 "Permintaan salah",
+		"error.BAD_REQUEST.details":                 "Format permintaan tidak benar",
+		"error.INVALID_DATA.message":                "Data tidak valid",
+		"error.INVALID_DATA.details":                "Data yang diberikan tidak valid",
+		"error.INVALID_FORMAT.message":              "Format tidak valid",
+		"error.INVALID_FORMAT.details":              "Silakan periksa format input Anda",
+		"error.INVALID_AGE.message":                 "Usia tidak valid",
+		"error.INVALID_AGE.details":                 "Usia tidak memenuhi persyaratan",
+		"error.MISSING_REQUIRED_DATA.message":       "Data wajib belum lengkap",
+		"error.MISSING_REQUIRED_DATA.details":       "Silakan lengkapi semua data wajib",
+		"error.DATE_EXPIRED.message":                "Tanggal sudah lewat",
+		"error.DATE_EXPIRED.details":                "Tanggal tersebut sudah terlewati",
+		"error.FILE_TYPE_NOT_SUPPORTED.message":     "Jenis berkas tidak didukung",
+		"error.FILE_TYPE_NOT_SUPPORTED.details":     "Silakan unggah jenis berkas yang didukung",
+		"error.DATA_NOT_FOUND.message":              "Data tidak ditemukan",
+		"error.DATA_NOT_FOUND.details":              "Data yang diminta tidak ada",
+		"error.EMAIL_EXIST.message":                 "Alamat email sudah terdaftar",
+		"error.EMAIL_EXIST.details":                 "Silakan gunakan alamat email lain",
+		"error.DATA_ALREADY_EXIST.message":          "Data sudah ada",
+		"error.DATA_ALREADY_EXIST.details":          "Data yang sama sudah tersimpan",
+		"error.CONFLICT.message":                    "Terjadi konflik",
+		"error.CONFLICT.details":                    "Data diubah oleh permintaan lain, silakan coba lagi",
+		"error.DATA_LOCKED.message":                 "Data sedang dikunci",
+		"error.DATA_LOCKED.details":                 "Data sedang diubah, silakan coba lagi sebentar lagi",
+		"error.FILE_NOT_PROCESSED_YET.message":      "Berkas belum diproses",
+		"error.FILE_NOT_PROCESSED_YET.details":      "Silakan coba lagi setelah pemrosesan selesai",
+		"error.METHOD_NOT_ALLOWED.message":          "Metode tidak diizinkan",
+		"error.METHOD_NOT_ALLOWED.details":          "Metode ini tidak didukung",
+		"error.ACCOUNT_LOCKED.message":              "Akun terkunci",
+		"error.ACCOUNT_LOCKED.details":              "Terlalu banyak percobaan gagal, silakan coba lagi nanti",
+		"error.ACCOUNT_BLOCKED.message":             "Akun diblokir",
+		"error.ACCOUNT_BLOCKED.details":             "Silakan hubungi dukungan",
+		"error.ACCOUNT_DELETED.message":             "Akun telah dihapus",
+		"error.ACCOUNT_DELETED.details":             "Akun ini sudah tidak ada",
+		"error.EMAIL_NOT_VERIFIED.message":          "Email belum diverifikasi",
+		"error.EMAIL_NOT_VERIFIED.details":          "Silakan verifikasi alamat email Anda terlebih dahulu",
+		"error.OTP_FAILED.message":                  "Verifikasi OTP gagal",
+		"error.OTP_FAILED.details":                  "Silakan minta kode baru",
+		"error.OTP_INVALID.message":                 "OTP tidak valid",
+		"error.OTP_INVALID.details":                 "Silakan periksa kode dan coba lagi",
+		"error.DUPLICATE_ENTRY.message":             "Data duplikat ditemukan",
+		"error.DUPLICATE_ENTRY.details":             "Data dengan nilai ini sudah ada",
+		"error.FOREIGN_KEY_VIOLATION.message":       "Referensi tidak valid",
+		"error.FOREIGN_KEY_VIOLATION.details":       "Data yang dirujuk tidak ada",
+		"error.REQUIRED_FIELD.message":              "Kolom wajib belum diisi",
+		"error.REQUIRED_FIELD.details":              "Silakan isi semua kolom wajib",
+		"error.INVALID_ID.message":                  "ID pengguna tidak valid",
+		"error.INVALID_ID.details":                  "ID pengguna harus berupa angka positif",
+		"error.QUERY_TOO_DEEP.message":              "Kueri terlalu dalam",
+		"error.QUERY_TOO_DEEP.details":              "Kurangi kedalaman operasi",
+		"error.QUERY_TOO_MANY_ALIASES.message":      "Kueri menggunakan terlalu banyak alias",
+		"error.QUERY_TOO_MANY_ALIASES.details":      "Kurangi jumlah alias dalam operasi",
+		"error.QUERY_TOO_COMPLEX.message":           "Kueri terlalu kompleks",
+		"error.QUERY_TOO_COMPLEX.details":           "Pilih lebih sedikit kolom atau halaman yang lebih kecil",
+		"error.QUOTA_LIMIT_REACHED.message":         "Operasi melebihi batas kuota permintaan",
+		"error.QUOTA_LIMIT_REACHED.details":         "Pilih lebih sedikit kolom dan daftar dalam satu operasi",
+		"error.REQUEST_TOO_FAST.message":            "Terlalu banyak permintaan",
+		"error.REQUEST_TOO_FAST.details":            "Silakan perlambat dan coba lagi nanti",
+		"error.GRAPHQL_PARSE_FAILED.message":        "Dokumen GraphQL tidak valid",
+		"error.GRAPHQL_PARSE_FAILED.details":        "Dokumen tidak dapat diurai",
+		"error.GRAPHQL_VALIDATION_FAILED.message":   "Operasi GraphQL tidak valid",
+		"error.GRAPHQL_VALIDATION_FAILED.details":   "Operasi tidak sesuai dengan skema",
+		"error.PERSISTED_QUERY_NOT_FOUND.details":   "Kirim dokumen lengkap bersama hash-nya",
+		"error.SERVICE_UNAVAILABLE.message":         "Layanan tidak tersedia",
+		"error.SERVICE_UNAVAILABLE.details":         "Silakan coba lagi nanti",
+		"error.REQUEST_FAILED.message":              "Permintaan gagal",
+		"error.REQUEST_FAILED.details":              "Permintaan ke layanan hulu gagal, silakan coba lagi",
+
+		"validation.required": "wajib diisi",
+		"validation.email":    "harus berupa alamat email yang valid",
+		"validation.min":      "minimal {0} karakter",
+		"validation.max":      "maksimal {0} karakter",
+		"validation.oneof":    "harus salah satu dari: {0}",
+		"validation.http_url": "harus berupa URL http atau https yang valid",
+		"validation.phone":    "harus berupa nomor telepon yang valid, misalnya +6281234567890 atau 081234567890",
+		"validation.whatsapp": "harus berupa nomor ponsel WhatsApp yang valid, misalnya +6281234567890",
+		"validation.default":  "tidak lolos validasi: {0}",
+	},
+}
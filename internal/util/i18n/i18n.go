@@ -0,0 +1,176 @@
+// Package i18n translates client-facing messages, negotiating the locale from
+// the user preference or the Accept-Language header.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+)
+
+const (
+	English    = "en"
+	Indonesian = "id"
+
+	DefaultLocale = English
+)
+
+var universal = ut.New(en.New(), en.New(), id.New())
+
+func init() {
+	for locale, messages := range catalogue {
+		trans, _ := universal.GetTranslator(locale)
+		for key, text := range messages {
+			if err := trans.Add(key, text, false); err != nil {
+				panic(fmt.Sprintf("i18n: %s %s: %v", locale, key, err))
+			}
+		}
+	}
+}
+
+// Add registers the message of key in locale, used by packages owning their
+// English texts. Call it from init functions only.
+func Add(locale, key, text string) {
+	if trans, ok := universal.GetTranslator(locale); ok {
+		if err := trans.Add(key, text, true); err != nil {
+			panic(fmt.Sprintf("i18n: %s %s: %v", locale, key, err))
+		}
+	}
+}
+
+type contextKey struct{}
+
+// Preference returns the locale chosen by the caller of a request, "" when
+// there is none
+type Preference func(ctx context.Context) string
+
+var preferences []Preference
+
+// RegisterPreference adds a source of user preferred locales, consulted
+// before Accept-Language. Call it from init functions only.
+func RegisterPreference(p Preference) {
+	preferences = append(preferences, p)
+}
+
+// Supported reports whether messages are translated to locale
+func Supported(locale string) bool {
+	_, ok := catalogue[locale]
+	return ok
+}
+
+// WithLocale returns a context carrying the negotiated locale
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// Locale returns the locale of a request: the user preference when set,
+// the one negotiated from Accept-Language otherwise
+func Locale(ctx context.Context) string {
+	for _, p := range preferences {
+		if locale := normalize(p(ctx)); Supported(locale) {
+			return locale
+		}
+	}
+	if locale, ok := ctx.Value(contextKey{}).(string); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// Negotiate picks the supported locale the client prefers from an
+// Accept-Language header, e.g. "id-ID,id;q=0.9,en;q=0.8"
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		locale  string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if value, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = value
+			}
+		}
+		if locale := normalize(tag); Supported(locale) && quality > 0 {
+			candidates = append(candidates, candidate{locale: locale, quality: quality})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	if len(candidates) > 0 {
+		return candidates[0].locale
+	}
+	return DefaultLocale
+}
+
+// T translates key to the locale of ctx, see Translate
+func T(ctx context.Context, key string, params ...string) (string, bool) {
+	return Translate(Locale(ctx), key, params...)
+}
+
+// Translate returns the message of key in locale, falling back to English.
+// Params replace the {0}, {1}... placeholders.
+func Translate(locale, key string, params ...string) (string, bool) {
+	for _, l := range []string{locale, DefaultLocale} {
+		trans, ok := universal.GetTranslator(l)
+		if !ok {
+			continue
+		}
+		if text, err := trans.T(key, params...); err == nil {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+// normalize reduces a language tag such as "id-ID" to its language
+func normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if base, _, ok := strings.Cut(tag, "-"); ok {
+		return base
+	}
+	if base, _, ok := strings.Cut(tag, "_"); ok {
+		return base
+	}
+	return tag
+}
+
+// Error translates the message and details of an error with code. Only the
+// catalogue texts are translated, texts built at runtime are kept as they are.
+func Error(ctx context.Context, code, message, details string) (string, string) {
+	locale := Locale(ctx)
+	if locale == DefaultLocale {
+		return message, details
+	}
+	return translateText(locale, "error."+code+".message", message), translateText(locale, "error."+code+".details", details)
+}
+
+// Validation returns the message of a failed validator rule
+func Validation(locale, rule, param string) string {
+	if text, ok := Translate(locale, "validation."+rule, param); ok {
+		return text
+	}
+	text, _ := Translate(locale, "validation.default", rule)
+	return text
+}
+
+func translateText(locale, key, text string) string {
+	if english, ok := Translate(DefaultLocale, key); !ok || english != text {
+		return text
+	}
+	if translated, ok := Translate(locale, key); ok {
+		return translated
+	}
+	return text
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/shennawardana23/graphql-pba/internal/util/requestid"
+	"github.com/sirupsen/logrus"
+)
+
+// ContextFields extracts log fields from a request context
+type ContextFields func(ctx context.Context) logrus.Fields
+
+var (
+	contextFieldsMu sync.RWMutex
+	contextFields   []ContextFields
+)
+
+// RegisterContextFields adds an extractor to the ones FromContext runs. It
+// lets packages the logger cannot import, such as auth, contribute fields.
+func RegisterContextFields(extract ContextFields) {
+	contextFieldsMu.Lock()
+	contextFields = append(contextFields, extract)
+	contextFieldsMu.Unlock()
+}
+
+// FromContext returns an entry carrying the request id, the operation name
+// and the fields of the registered extractors, such as the user id
+func FromContext(ctx context.Context) *logrus.Entry {
+	if ctx == nil {
+		return logrus.NewEntry(Log)
+	}
+	return Log.WithContext(ctx).WithFields(fieldsFromContext(ctx))
+}
+
+func fieldsFromContext(ctx context.Context) logrus.Fields {
+	fields := logrus.Fields{}
+	if ctx == nil {
+		return fields
+	}
+
+	if id := requestid.FromContext(ctx); id != "" {
+		fields["request_id"] = id
+	}
+	if graphql.HasOperationContext(ctx) {
+		if name := operationName(graphql.GetOperationContext(ctx)); name != "" {
+			fields["operation"] = name
+		}
+	}
+
+	contextFieldsMu.RLock()
+	defer contextFieldsMu.RUnlock()
+	for _, extract := range contextFields {
+		for k, v := range extract(ctx) {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+func operationName(oc *graphql.OperationContext) string {
+	if oc.OperationName != "" {
+		return oc.OperationName
+	}
+	if oc.Operation != nil {
+		return oc.Operation.Name
+	}
+	return ""
+}
+
+// Context variants of the helpers below, with the fields of FromContext
+func InfoContext(ctx context.Context, args ...interface{}) {
+	withCaller(FromContext(ctx)).Info(args...)
+}
+
+func ErrorContext(ctx context.Context, args ...interface{}) {
+	withCaller(FromContext(ctx)).Error(args...)
+}
+
+func WarnContext(ctx context.Context, args ...interface{}) {
+	withCaller(FromContext(ctx)).Warn(args...)
+}
+
+func DebugContext(ctx context.Context, args ...interface{}) {
+	withCaller(FromContext(ctx)).Debug(args...)
+}
+
+func FatalContext(ctx context.Context, args ...interface{}) {
+	withCaller(FromContext(ctx)).Fatal(args...)
+}
+
+// withCaller adds the file and line of the caller of the exported helper
+func withCaller(entry *logrus.Entry) *logrus.Entry {
+	_, file, line, _ := runtime.Caller(2)
+	return entry.WithFields(logrus.Fields{
+		"file": filepath.Base(file),
+		"line": line,
+	})
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// rotatingFile rotates the log file once it reaches the maximum size and,
+// when an interval is set, on every interval, keeping a bounded number of
+// backups for a bounded number of days
+type rotatingFile struct {
+	*lumberjack.Logger
+
+	stop chan struct{}
+	once sync.Once
+}
+
+func newRotatingFile(config Config) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(config.File), 0o755); err != nil {
+		return nil, fmt.Errorf("create log directory: %w", err)
+	}
+
+	f := &rotatingFile{
+		Logger: &lumberjack.Logger{
+			Filename:   config.File,
+			MaxSize:    config.MaxSizeMB,
+			MaxAge:     config.MaxAgeDays,
+			MaxBackups: config.MaxBackups,
+			Compress:   config.Compress,
+			LocalTime:  true,
+		},
+		stop: make(chan struct{}),
+	}
+
+	if config.RotateInterval > 0 {
+		go f.rotateEvery(config.RotateInterval)
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) rotateEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := f.Rotate(); err != nil {
+				fmt.Fprintf(os.Stderr, "logger: rotate %s: %v\n", f.Filename, err)
+			}
+		case <-f.stop:
+			return
+		}
+	}
+}
+
+func (f *rotatingFile) Close() error {
+	f.once.Do(func() { close(f.stop) })
+	return f.Logger.Close()
+}
@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	fluentQueueSize    = 1024
+	fluentDialTimeout  = 3 * time.Second
+	fluentWriteTimeout = 5 * time.Second
+	fluentMaxBackoff   = 30 * time.Second
+	fluentFlushTimeout = 5 * time.Second
+)
+
+// fluentWriter ships JSON formatted entries to Fluent Bit using the forward
+// protocol in message mode. Writes never block logging: records are queued
+// and dropped while the queue is full, e.g. when Fluent Bit is unreachable.
+type fluentWriter struct {
+	addr string
+	tag  string
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan []byte
+	stop   chan struct{}
+	done   chan struct{}
+
+	conn    net.Conn
+	dropped atomic.Int64
+}
+
+func newFluentWriter(host string, port int, tag string) *fluentWriter {
+	w := &fluentWriter{
+		addr:  net.JoinHostPort(host, strconv.Itoa(port)),
+		tag:   tag,
+		queue: make(chan []byte, fluentQueueSize),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *fluentWriter) Write(p []byte) (int, error) {
+	// The formatter may reuse its buffer
+	line := append([]byte(nil), p...)
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return len(p), nil
+	}
+
+	select {
+	case w.queue <- line:
+	default:
+		w.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Close sends the queued records, waiting at most fluentFlushTimeout. The
+// records are dropped as soon as a send fails.
+func (w *fluentWriter) Close() error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+		close(w.stop)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+	case <-time.After(fluentFlushTimeout):
+	}
+	return nil
+}
+
+func (w *fluentWriter) run() {
+	defer close(w.done)
+	defer func() {
+		if w.conn != nil {
+			w.conn.Close()
+		}
+	}()
+
+	backoff := time.Second
+	for line := range w.queue {
+		message, err := w.encode(line)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "logger: encode fluent record: %v\n", err)
+			continue
+		}
+
+		for {
+			if err := w.send(message); err == nil {
+				backoff = time.Second
+				break
+			} else {
+				fmt.Fprintf(os.Stderr, "logger: send to fluent %s: %v, retrying in %s\n", w.addr, err, backoff)
+			}
+
+			// Keep the newest records rather than waiting on a dead peer forever
+			if len(w.queue) == cap(w.queue) {
+				break
+			}
+			// Nobody waits for the remaining records once closed
+			select {
+			case <-w.stop:
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, fluentMaxBackoff)
+		}
+	}
+}
+
+// encode turns one JSON entry into a [tag, time, record] forward message
+func (w *fluentWriter) encode(line []byte) ([]byte, error) {
+	var record map[string]interface{}
+	if err := json.Unmarshal(line, &record); err != nil {
+		return nil, err
+	}
+
+	timestamp := time.Now()
+	if value, ok := record["time"].(string); ok {
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			timestamp = parsed
+		}
+	}
+	if dropped := w.dropped.Swap(0); dropped > 0 {
+		record["fluent_dropped"] = dropped
+	}
+
+	return msgpack.Marshal([]interface{}{w.tag, timestamp.Unix(), record})
+}
+
+func (w *fluentWriter) send(message []byte) error {
+	if w.conn == nil {
+		conn, err := net.DialTimeout("tcp", w.addr, fluentDialTimeout)
+		if err != nil {
+			return err
+		}
+		w.conn = conn
+	}
+
+	w.conn.SetWriteDeadline(time.Now().Add(fluentWriteTimeout))
+	if _, err := w.conn.Write(message); err != nil {
+		w.conn.Close()
+		w.conn = nil
+		return err
+	}
+	return nil
+}
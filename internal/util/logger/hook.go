@@ -0,0 +1,22 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// contextHook adds the fields of FromContext to every entry logged with a
+// request context, however it was created
+type contextHook struct{}
+
+func (contextHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (contextHook) Fire(entry *logrus.Entry) error {
+	for k, v := range fieldsFromContext(entry.Context) {
+		if _, ok := entry.Data[k]; !ok {
+			entry.Data[k] = v
+		}
+	}
+	return nil
+}
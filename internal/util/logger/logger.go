@@ -5,17 +5,25 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 
 	"github.com/sirupsen/logrus"
 )
 
 var Log *logrus.Logger
 
+// CustomFormatter is the human readable "pretty" format. Colors should only
+// be enabled when the output is a terminal.
 type CustomFormatter struct {
 	logrus.TextFormatter
+	Colors bool
 }
 
 func (f *CustomFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if !f.Colors {
+		return f.formatPlain(entry), nil
+	}
+
 	// Custom timestamp format
 	timestamp := entry.Time.Format("2006-01-02 15:04:05")
 
@@ -45,7 +53,7 @@ func (f *CustomFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 
 	// Add level
 	msg = append(msg, []byte(
-		"\x1b["+string(rune(levelColor))+"m"+entry.Level.String()+"\x1b[0m")...,
+		"\x1b["+strconv.Itoa(levelColor)+"m"+entry.Level.String()+"\x1b[0m")...,
 	)
 	msg = append(msg, []byte(" | ")...)
 
@@ -77,17 +85,37 @@ func (f *CustomFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	return msg, nil
 }
 
+// formatPlain is the pretty layout without escape codes
+func (f *CustomFormatter) formatPlain(entry *logrus.Entry) []byte {
+	msg := []byte(entry.Time.Format("2006-01-02 15:04:05") + " | " + entry.Level.String() + " | ")
+
+	if file, ok := entry.Data["file"]; ok {
+		if line, ok := entry.Data["line"]; ok {
+			msg = append(msg, fmt.Sprintf("%s:%d | ", file, line)...)
+		}
+	}
+
+	fields := 0
+	for k, v := range entry.Data {
+		if k != "file" && k != "line" {
+			msg = append(msg, fmt.Sprintf("%s=%v ", k, v)...)
+			fields++
+		}
+	}
+	if fields > 0 {
+		msg = append(msg, "| "...)
+	}
+
+	msg = append(msg, entry.Message...)
+	return append(msg, '\n')
+}
+
 func init() {
 	Log = logrus.New()
 	Log.SetOutput(os.Stdout)
 	Log.SetReportCaller(true) // Enable caller reporting
-	Log.SetFormatter(&CustomFormatter{
-		TextFormatter: logrus.TextFormatter{
-			FullTimestamp:          true,
-			TimestampFormat:        "2006-01-02 15:04:05",
-			DisableLevelTruncation: true,
-		},
-	})
+	Log.AddHook(contextHook{})
+	Log.SetFormatter(newFormatter(FormatPretty, isTerminal(os.Stdout)))
 
 	// Set log level from environment variable or default to Info
 	level := os.Getenv("LOG_LEVEL")
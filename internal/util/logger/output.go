@@ -0,0 +1,261 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/sirupsen/logrus"
+)
+
+// Log formats
+const (
+	FormatJSON   = "json"
+	FormatText   = "text"
+	FormatPretty = "pretty"
+)
+
+// Log outputs, several can be enabled at once
+const (
+	OutputStdout = "stdout"
+	OutputFile   = "file"
+	OutputFluent = "fluent"
+)
+
+type Config struct {
+	Level   string
+	Format  string
+	Outputs []string
+
+	File           string
+	MaxSizeMB      int
+	MaxAgeDays     int
+	MaxBackups     int
+	Compress       bool
+	RotateInterval time.Duration
+
+	FluentHost string
+	FluentPort int
+	FluentTag  string
+}
+
+func NewConfig() Config {
+	return Config{
+		Level:   getEnvOrDefault("LOG_LEVEL", "info"),
+		Format:  strings.ToLower(getEnvOrDefault("LOG_FORMAT", FormatPretty)),
+		Outputs: splitList(getEnvOrDefault("LOG_OUTPUTS", OutputStdout)),
+
+		File:           getEnvOrDefault("LOG_FILE", "logs/app.log"),
+		MaxSizeMB:      getEnvAsInt("LOG_MAX_SIZE_MB", 100),
+		MaxAgeDays:     getEnvAsInt("LOG_MAX_AGE_DAYS", 7),
+		MaxBackups:     getEnvAsInt("LOG_MAX_BACKUPS", 10),
+		Compress:       getEnvOrDefault("LOG_COMPRESS", "false") == "true",
+		RotateInterval: getEnvAsDuration("LOG_ROTATE_INTERVAL", 24*time.Hour),
+
+		FluentHost: getEnvOrDefault("FLUENT_HOST", "localhost"),
+		FluentPort: getEnvAsInt("FLUENT_PORT", 24224),
+		FluentTag:  getEnvOrDefault("FLUENT_TAG", "graphql-pba"),
+	}
+}
+
+// Setup sends the logs to every configured output, each with its own
+// formatter. The returned closer flushes and closes the outputs.
+func Setup(config Config) (io.Closer, error) {
+	level, err := logrus.ParseLevel(config.Level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOG_LEVEL %q: %w", config.Level, err)
+	}
+	switch config.Format {
+	case FormatJSON, FormatText, FormatPretty:
+	default:
+		return nil, fmt.Errorf("invalid LOG_FORMAT %q, expected json, text or pretty", config.Format)
+	}
+	if len(config.Outputs) == 0 {
+		return nil, errors.New("LOG_OUTPUTS is empty")
+	}
+
+	var sinks []*sink
+	closeAll := closers{}
+	for _, output := range config.Outputs {
+		var s *sink
+		switch output {
+		case OutputStdout:
+			s = newSink(os.Stdout, newFormatter(config.Format, isTerminal(os.Stdout)))
+		case OutputFile:
+			file, err := newRotatingFile(config)
+			if err != nil {
+				closeAll.Close()
+				return nil, err
+			}
+			closeAll = append(closeAll, file)
+			s = newSink(file, newFormatter(config.Format, false))
+		case OutputFluent:
+			forward := newFluentWriter(config.FluentHost, config.FluentPort, config.FluentTag)
+			closeAll = append(closeAll, forward)
+			// Fluent Bit gets structured records whatever the format
+			s = newSink(forward, &logrus.JSONFormatter{})
+		default:
+			closeAll.Close()
+			return nil, fmt.Errorf("invalid log output %q, expected stdout, file or fluent", output)
+		}
+		sinks = append(sinks, s)
+	}
+
+	// The context fields must be added before the sinks format the entry
+	hooks := logrus.LevelHooks{}
+	hooks.Add(contextHook{})
+	hooks.Add(callerHook{})
+	for _, s := range sinks {
+		hooks.Add(s)
+	}
+
+	Log.ReplaceHooks(hooks)
+	Log.SetFormatter(discardFormatter{})
+	Log.SetOutput(io.Discard)
+	Log.SetLevel(level)
+
+	// Flush the outputs before Fatal exits the process
+	logrus.RegisterExitHandler(func() { closeAll.Close() })
+
+	return closeAll, nil
+}
+
+// sink writes the entries of every level to one output
+type sink struct {
+	mu        sync.Mutex
+	out       io.Writer
+	formatter logrus.Formatter
+}
+
+func newSink(out io.Writer, formatter logrus.Formatter) *sink {
+	return &sink{out: out, formatter: formatter}
+}
+
+func (s *sink) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (s *sink) Fire(entry *logrus.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The location is already in the file and line fields, the logrus
+	// formatters would add it a second time
+	e := *entry
+	e.Caller = nil
+
+	line, err := s.formatter.Format(&e)
+	if err != nil {
+		return err
+	}
+	_, err = s.out.Write(line)
+	return err
+}
+
+// callerHook adds the file and line fields to entries logged without the
+// helpers of this package
+type callerHook struct{}
+
+func (callerHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (callerHook) Fire(entry *logrus.Entry) error {
+	if entry.Caller == nil {
+		return nil
+	}
+	if _, ok := entry.Data["file"]; !ok {
+		entry.Data["file"] = filepath.Base(entry.Caller.File)
+		entry.Data["line"] = entry.Caller.Line
+	}
+	return nil
+}
+
+// discardFormatter skips formatting for the logger's own output, the sinks
+// format each entry themselves
+type discardFormatter struct{}
+
+func (discardFormatter) Format(*logrus.Entry) ([]byte, error) {
+	return nil, nil
+}
+
+func newFormatter(format string, colors bool) logrus.Formatter {
+	switch format {
+	case FormatJSON:
+		return &logrus.JSONFormatter{TimestampFormat: time.RFC3339Nano}
+	case FormatText:
+		return &logrus.TextFormatter{
+			DisableColors:   true,
+			FullTimestamp:   true,
+			TimestampFormat: time.RFC3339,
+		}
+	default:
+		return &CustomFormatter{
+			TextFormatter: logrus.TextFormatter{
+				FullTimestamp:          true,
+				TimestampFormat:        "2006-01-02 15:04:05",
+				DisableLevelTruncation: true,
+			},
+			Colors: colors,
+		}
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+type closers []io.Closer
+
+func (c closers) Close() error {
+	var errs []error
+	for _, closer := range c {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Helper functions for environment variables
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
@@ -1,12 +1,18 @@
 package validation_model
 
 import (
+	"strings"
+
 	"github.com/shennawardana23/graphql-pba/graph/model"
+	"github.com/shennawardana23/graphql-pba/internal/util/validator"
 )
 
+// Validation models carry the GraphQL field names in their json tags, so
+// field errors point at the input field
+
 type ValidationNewUser struct {
-	Name  string `validate:"required,min=2,max=100"`
-	Email string `validate:"required,email,max=255"`
+	Name  string `json:"name" validate:"required,min=2,max=100"`
+	Email string `json:"email" validate:"required,email,max=255"`
 }
 
 func NewUserFromGQL(input model.NewUser) ValidationNewUser {
@@ -15,3 +21,122 @@ func NewUserFromGQL(input model.NewUser) ValidationNewUser {
 		Email: input.Email,
 	}
 }
+
+type ValidationUpdateUser struct {
+	Name   *string `json:"name" validate:"omitempty,min=2,max=100"`
+	Email  *string `json:"email" validate:"omitempty,email,max=255"`
+	Locale *string `json:"locale" validate:"omitempty,oneof=en id"`
+}
+
+func UpdateUserFromGQL(input model.UpdateUserInput) ValidationUpdateUser {
+	return ValidationUpdateUser{
+		Name:   input.Name,
+		Email:  input.Email,
+		Locale: input.Locale,
+	}
+}
+
+type ValidationRegister struct {
+	Name     string `json:"name" validate:"required,min=2,max=100"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+func RegisterFromGQL(input model.RegisterInput) ValidationRegister {
+	return ValidationRegister{
+		Name:     input.Name,
+		Email:    input.Email,
+		Password: input.Password,
+	}
+}
+
+// ValidationNewRestaurant holds the trimmed restaurant input, optional fields
+// are empty when not given
+type ValidationNewRestaurant struct {
+	RestaurantName     string `json:"restaurantName" validate:"required,min=2,max=255"`
+	RestaurantLogo     string `json:"restaurantLogo" validate:"required,http_url,max=2048"`
+	RestaurantFavicon  string `json:"restaurantFavicon" validate:"omitempty,http_url,max=2048"`
+	ThumbnailDesktop   string `json:"thumbnailDesktop" validate:"required,http_url,max=2048"`
+	RestaurantPhone    string `json:"restaurantPhone" validate:"omitempty,phone"`
+	RestaurantWhatsapp string `json:"restaurantWhatsapp" validate:"omitempty,whatsapp"`
+	RestaurantEmail    string `json:"restaurantEmail" validate:"omitempty,email,max=255"`
+	RestaurantAddress  string `json:"restaurantAddress" validate:"omitempty,max=1000"`
+	RestaurantWebsite  string `json:"restaurantWebsite" validate:"omitempty,http_url,max=2048"`
+}
+
+func NewRestaurantFromGQL(input model.NewRestaurant) ValidationNewRestaurant {
+	return ValidationNewRestaurant{
+		RestaurantName:     strings.TrimSpace(input.RestaurantName),
+		RestaurantLogo:     strings.TrimSpace(input.RestaurantLogo),
+		RestaurantFavicon:  trimmed(input.RestaurantFavicon),
+		ThumbnailDesktop:   strings.TrimSpace(input.ThumbnailDesktop),
+		RestaurantPhone:    trimmed(input.RestaurantPhone),
+		RestaurantWhatsapp: trimmed(input.RestaurantWhatsapp),
+		RestaurantEmail:    trimmed(input.RestaurantEmail),
+		RestaurantAddress:  trimmed(input.RestaurantAddress),
+		RestaurantWebsite:  trimmed(input.RestaurantWebsite),
+	}
+}
+
+// Normalize rewrites the phone numbers to E.164, call it once validated
+func (v *ValidationNewRestaurant) Normalize() {
+	v.RestaurantPhone = normalizePhone(v.RestaurantPhone, validator.NormalizePhone)
+	v.RestaurantWhatsapp = normalizePhone(v.RestaurantWhatsapp, validator.NormalizeWhatsapp)
+}
+
+// ValidationUpdateRestaurant holds the trimmed fields to update. Required
+// fields are pointers, nil when not given, so they cannot be emptied;
+// optional fields are cleared with an empty string.
+type ValidationUpdateRestaurant struct {
+	RestaurantName     *string `json:"restaurantName" validate:"omitempty,min=2,max=255"`
+	RestaurantLogo     *string `json:"restaurantLogo" validate:"omitempty,http_url,max=2048"`
+	RestaurantFavicon  string  `json:"restaurantFavicon" validate:"omitempty,http_url,max=2048"`
+	ThumbnailDesktop   *string `json:"thumbnailDesktop" validate:"omitempty,http_url,max=2048"`
+	RestaurantPhone    string  `json:"restaurantPhone" validate:"omitempty,phone"`
+	RestaurantWhatsapp string  `json:"restaurantWhatsapp" validate:"omitempty,whatsapp"`
+	RestaurantEmail    string  `json:"restaurantEmail" validate:"omitempty,email,max=255"`
+	RestaurantAddress  string  `json:"restaurantAddress" validate:"omitempty,max=1000"`
+	RestaurantWebsite  string  `json:"restaurantWebsite" validate:"omitempty,http_url,max=2048"`
+}
+
+func UpdateRestaurantFromGQL(input model.UpdateRestaurantInput) ValidationUpdateRestaurant {
+	return ValidationUpdateRestaurant{
+		RestaurantName:     trimmedPtr(input.RestaurantName),
+		RestaurantLogo:     trimmedPtr(input.RestaurantLogo),
+		RestaurantFavicon:  trimmed(input.RestaurantFavicon),
+		ThumbnailDesktop:   trimmedPtr(input.ThumbnailDesktop),
+		RestaurantPhone:    trimmed(input.RestaurantPhone),
+		RestaurantWhatsapp: trimmed(input.RestaurantWhatsapp),
+		RestaurantEmail:    trimmed(input.RestaurantEmail),
+		RestaurantAddress:  trimmed(input.RestaurantAddress),
+		RestaurantWebsite:  trimmed(input.RestaurantWebsite),
+	}
+}
+
+// Normalize rewrites the phone numbers to E.164, call it once validated
+func (v *ValidationUpdateRestaurant) Normalize() {
+	v.RestaurantPhone = normalizePhone(v.RestaurantPhone, validator.NormalizePhone)
+	v.RestaurantWhatsapp = normalizePhone(v.RestaurantWhatsapp, validator.NormalizeWhatsapp)
+}
+
+func trimmed(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
+
+func trimmedPtr(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	value := strings.TrimSpace(*s)
+	return &value
+}
+
+func normalizePhone(phone string, normalize func(string) (string, bool)) string {
+	if normalized, ok := normalize(phone); ok {
+		return normalized
+	}
+	return phone
+}
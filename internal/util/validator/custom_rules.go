@@ -6,20 +6,25 @@ import (
 
 // RegisterCustomValidations adds custom validation rules to the validator
 func RegisterCustomValidations(v *validator.Validate) {
-	// Register custom validations
-	_ = v.RegisterValidation("customtag", customValidation)
-	_ = v.RegisterValidation("phone", validatePhone)
-	// Add more custom validations as needed
+	// The rules are registered once at startup, an error is a bug
+	for tag, fn := range map[string]validator.Func{
+		"phone":    validatePhone,
+		"whatsapp": validateWhatsapp,
+	} {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			panic(err)
+		}
+	}
 }
 
-// Example custom validation
-func customValidation(fl validator.FieldLevel) bool {
-	// Your custom validation logic
-	return true
+// Phone number validation, see NormalizePhone
+func validatePhone(fl validator.FieldLevel) bool {
+	_, ok := NormalizePhone(fl.Field().String())
+	return ok
 }
 
-// Phone number validation
-func validatePhone(fl validator.FieldLevel) bool {
-	// Phone validation logic
-	return true
+// WhatsApp number validation, see NormalizeWhatsapp
+func validateWhatsapp(fl validator.FieldLevel) bool {
+	_, ok := NormalizeWhatsapp(fl.Field().String())
+	return ok
 }
@@ -1,38 +1,69 @@
 package validator
 
 import (
-	"context"
+	"errors"
+	"fmt"
 	"strings"
 
-	"github.com/99designs/gqlgen/graphql"
 	"github.com/go-playground/validator/v10"
-	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/shennawardana23/graphql-pba/internal/util/exception"
+	"github.com/shennawardana23/graphql-pba/internal/util/i18n"
 )
 
-func translateValidationError(ctx context.Context, err error) *gqlerror.Error {
+func translateValidationError(argument string, err error) error {
 	if err == nil {
 		return nil
 	}
 
-	validationErrors, ok := err.(validator.ValidationErrors)
-	if !ok {
-		return &gqlerror.Error{
-			Message: "Validation failed",
-			Path:    graphql.GetPath(ctx),
-		}
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		// Data was not a struct, a bug rather than bad input
+		return exception.ErrInternalServer.Wrap(err)
 	}
 
-	var messages []string
+	fields := make([]exception.FieldError, 0, len(validationErrors))
 	for _, e := range validationErrors {
-		messages = append(messages, translateFieldError(e))
+		fields = append(fields, exception.FieldError{
+			Field: fieldPath(argument, e),
+			Rule:  e.Tag(),
+			Param: e.Param(),
+		})
 	}
 
-	return &gqlerror.Error{
-		Message: "Validation failed",
-		Path:    graphql.GetPath(ctx),
-		Extensions: map[string]interface{}{
-			"code":    "VALIDATION_ERROR",
-			"details": strings.Join(messages, "; "),
-		},
+	details, fields := Localize(i18n.DefaultLocale, fields)
+	return exception.NewFieldValidationError(details, fields)
+}
+
+// Localize returns fields with their messages in locale, and the details
+// summing them up
+func Localize(locale string, fields []exception.FieldError) (string, []exception.FieldError) {
+	localized := make([]exception.FieldError, len(fields))
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		field.Message = i18n.Validation(locale, field.Rule, field.Param)
+		localized[i] = field
+		messages[i] = fmt.Sprintf("%s: %s", fieldName(field.Field), field.Message)
+	}
+	return strings.Join(messages, "; "), localized
+}
+
+// fieldName returns the last segment of an input path in lower case
+func fieldName(path string) string {
+	if i := strings.LastIndexByte(path, '.'); i >= 0 {
+		path = path[i+1:]
+	}
+	return strings.ToLower(path)
+}
+
+// fieldPath turns the namespace of e, e.g. ValidationNewUser.email, into the
+// input path of the field, e.g. input.email
+func fieldPath(argument string, e validator.FieldError) string {
+	path := e.Namespace()
+	if i := strings.IndexByte(path, '.'); i >= 0 {
+		path = path[i+1:]
+	}
+	if argument == "" {
+		return path
 	}
+	return argument + "." + path
 }
@@ -0,0 +1,69 @@
+package validator
+
+import "strings"
+
+// DefaultCallingCode is the country calling code of numbers written in the
+// national format, e.g. 0812-3456-7890: Indonesia
+const DefaultCallingCode = "62"
+
+// NormalizePhone returns phone in E.164 format, e.g. +6281234567890. It
+// accepts international numbers written with + or 00 and national numbers
+// of the default region written with a leading 0 or its calling code.
+func NormalizePhone(phone string) (string, bool) {
+	digits := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(phone))
+
+	switch {
+	case strings.HasPrefix(digits, "+"):
+		digits = digits[1:]
+	case strings.HasPrefix(digits, "00"):
+		digits = digits[2:]
+	case strings.HasPrefix(digits, "0"):
+		digits = DefaultCallingCode + digits[1:]
+	case strings.HasPrefix(digits, DefaultCallingCode):
+	default:
+		return "", false
+	}
+
+	// E.164 allows at most 15 digits and no leading 0 in the country code
+	if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' || !isDigits(digits) {
+		return "", false
+	}
+
+	// Indonesian subscriber numbers have 8 to 12 digits after the country
+	// code, without a trunk prefix
+	if national, ok := strings.CutPrefix(digits, "62"); ok {
+		if len(national) < 8 || len(national) > 12 || national[0] == '0' {
+			return "", false
+		}
+	}
+	return "+" + digits, true
+}
+
+// NormalizeWhatsapp returns the E.164 format of a number that can hold a
+// WhatsApp account. Indonesian numbers must be mobile numbers, starting with
+// 8 after the country code; other regions are only checked to be valid.
+func NormalizeWhatsapp(phone string) (string, bool) {
+	normalized, ok := NormalizePhone(phone)
+	if !ok {
+		return "", false
+	}
+	if national, ok := strings.CutPrefix(normalized, "+62"); ok && national[0] != '8' {
+		return "", false
+	}
+	return normalized, true
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package validator
+
+import "testing"
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		name  string
+		phone string
+		want  string
+		ok    bool
+	}{
+		{"national mobile", "081234567890", "+6281234567890", true},
+		{"national landline", "0215551234", "+62215551234", true},
+		{"international", "+6281234567890", "+6281234567890", true},
+		{"calling code without plus", "6281234567890", "+6281234567890", true},
+		{"double zero prefix", "006281234567890", "+6281234567890", true},
+		{"spaces", " 0812 3456 7890 ", "+6281234567890", true},
+		{"dashes", "0812-3456-7890", "+6281234567890", true},
+		{"dots and parentheses", "(021) 555.1234", "+62215551234", true},
+		{"foreign", "+1 415-555-2671", "+14155552671", true},
+		{"foreign with double zero", "0044 20 7946 0958", "+442079460958", true},
+		{"foreign without plus", "14155552671", "", false},
+		{"national too short", "0812345", "", false},
+		{"national too long", "08123456789012", "", false},
+		{"trunk prefix after calling code", "+62081234567", "", false},
+		{"over fifteen digits", "+1234567890123456", "", false},
+		{"international too short", "+123456", "", false},
+		{"country code starting with zero", "+0123456789", "", false},
+		{"letters", "0812-CALL-NOW", "", false},
+		{"plus inside", "0812+34567890", "", false},
+		{"empty", "", "", false},
+		{"only separators", " - ", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NormalizePhone(tt.phone)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("NormalizePhone(%q) = %q, %v; want %q, %v", tt.phone, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNormalizeWhatsapp(t *testing.T) {
+	tests := []struct {
+		name  string
+		phone string
+		want  string
+		ok    bool
+	}{
+		{"indonesian mobile", "0812-3456-7890", "+6281234567890", true},
+		{"indonesian landline", "021 555 1234", "", false},
+		{"foreign", "+14155552671", "+14155552671", true},
+		{"invalid", "12345", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NormalizeWhatsapp(tt.phone)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("NormalizeWhatsapp(%q) = %q, %v; want %q, %v", tt.phone, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -1,17 +1,27 @@
 package validator
 
 import (
-	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
-	"github.com/shennawardana23/graphql-pba/internal/util/exception"
 )
 
 var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+
+	// Report fields by their GraphQL name, taken from the json tag
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+
+	RegisterCustomValidations(validate)
 }
 
 type Validator struct {
@@ -24,36 +34,14 @@ func New() *Validator {
 	}
 }
 
+// ValidateStruct validates data, reporting fields relative to it
 func (v *Validator) ValidateStruct(data interface{}) error {
-	if err := v.validate.Struct(data); err != nil {
-		validationErrors := err.(validator.ValidationErrors)
-		var messages []string
-		for _, e := range validationErrors {
-			messages = append(messages, translateFieldError(e))
-		}
-
-		return &exception.CustomError{
-			Code:    "VALIDATION_ERROR",
-			Message: "Validation failed",
-			Details: strings.Join(messages, "; "),
-		}
-	}
-	return nil
+	return v.ValidateInput("", data)
 }
 
-func translateFieldError(e validator.FieldError) string {
-	field := strings.ToLower(e.Field())
-
-	switch e.Tag() {
-	case "required":
-		return fmt.Sprintf("%s: must be provided", field)
-	case "email":
-		return fmt.Sprintf("%s: must be a valid email address", field)
-	case "min":
-		return fmt.Sprintf("%s: must be at least %s characters", field, e.Param())
-	case "max":
-		return fmt.Sprintf("%s: must not exceed %s characters", field, e.Param())
-	default:
-		return fmt.Sprintf("%s: failed validation: %s", field, e.Tag())
-	}
+// ValidateInput validates data passed as the argument of a GraphQL field,
+// reporting every failed rule in extensions.fieldErrors under its input path,
+// e.g. input.email
+func (v *Validator) ValidateInput(argument string, data interface{}) error {
+	return translateValidationError(argument, v.validate.Struct(data))
 }
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_restaurants_user_id;
+DROP TABLE IF EXISTS restaurants;
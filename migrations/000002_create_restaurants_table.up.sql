@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS restaurants (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    restaurant_name VARCHAR(255) NOT NULL,
+    restaurant_logo TEXT NOT NULL,
+    restaurant_favicon TEXT,
+    thumbnail_desktop TEXT NOT NULL,
+    restaurant_phone VARCHAR(32) DEFAULT '',
+    restaurant_whatsapp VARCHAR(32) DEFAULT '',
+    restaurant_email VARCHAR(255) DEFAULT '',
+    restaurant_address TEXT,
+    restaurant_website TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_restaurants_user_id ON restaurants(user_id);
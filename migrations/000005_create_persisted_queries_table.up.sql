@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS persisted_queries (
+    hash VARCHAR(64) PRIMARY KEY,
+    query TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
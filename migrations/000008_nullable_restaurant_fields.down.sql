@@ -0,0 +1,4 @@
+ALTER TABLE restaurants
+    ALTER COLUMN restaurant_phone SET DEFAULT '',
+    ALTER COLUMN restaurant_whatsapp SET DEFAULT '',
+    ALTER COLUMN restaurant_email SET DEFAULT '';
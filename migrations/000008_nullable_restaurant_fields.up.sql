@@ -0,0 +1,12 @@
+ALTER TABLE restaurants
+    ALTER COLUMN restaurant_phone DROP DEFAULT,
+    ALTER COLUMN restaurant_whatsapp DROP DEFAULT,
+    ALTER COLUMN restaurant_email DROP DEFAULT;
+
+UPDATE restaurants SET
+    restaurant_favicon = NULLIF(restaurant_favicon, ''),
+    restaurant_phone = NULLIF(restaurant_phone, ''),
+    restaurant_whatsapp = NULLIF(restaurant_whatsapp, ''),
+    restaurant_email = NULLIF(restaurant_email, ''),
+    restaurant_address = NULLIF(restaurant_address, ''),
+    restaurant_website = NULLIF(restaurant_website, '');
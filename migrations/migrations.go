@@ -0,0 +1,8 @@
+// Package migrations embeds the versioned SQL migrations applied by the
+// `migrate` command. Files are named <version>_<name>.<up|down>.sql.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS